@@ -3,92 +3,188 @@
 // tags, constructor methods, and explicit bindings.
 //
 // All public methods on App are safe for concurrent use from multiple goroutines.
+// Resolutions run in parallel; the registries are only locked while read or
+// written, never for the duration of a Make.
 // Errors are reported via panic, not returned errors.
 package di
 
 import (
+	"context"
 	"fmt"
 	"reflect"
-	"runtime"
 	"strconv"
 	"sync"
 	"sync/atomic"
 )
 
-// AppInterface defines the public contract for a DI container.
+// AppInterface defines the public contract for a DI container. It covers
+// every public method of App, so decorators and mocks can wrap an App by
+// embedding it. Some signatures still name *App: ServiceProvider methods and
+// ContextBindFunc receive the concrete container, and Override only accepts
+// chains from When on an App, so a wholly separate implementation cannot
+// stand in for App there.
 type AppInterface interface {
 	New(...AppConfig) AppInterface
 	Bind(interface{}, interface{}) AppInterface
 	Singleton(interface{}, ...interface{}) AppInterface
+	BindDefault(interface{}, interface{}) AppInterface
+	BindConfig(...ConfigSource) AppInterface
+	RegisterConverter(reflect.Type, func(string) (interface{}, error)) AppInterface
+	Register(...interface{}) AppInterface
+	RegisterProvider(ServiceProvider) AppInterface
+	Boot() error
+	Freeze() AppInterface
 	Make(interface{}) interface{}
 	MakeWith(interface{}, map[string]interface{}) interface{}
-	When(a interface{}) *whenLink
+	MakeContext(context.Context, interface{}) interface{}
+	Context() context.Context
+	When(interface{}, ...interface{}) WhenBuilder
+	Override(interface{}, interface{}) func()
+	Snapshot() *Snapshot
+	Restore(*Snapshot) AppInterface
+	Clone(...CloneConfig) AppInterface
+	Scope() AppInterface
+	Dispose() error
 }
 
 // BindFunc is a factory function that receives the container and returns
 // an instance. Used with Bind and Singleton to construct dependencies.
+//
+// The *App passed in is a resolver scoped to the call that triggered the
+// BindFunc: Make, Bind etc. on it join the in-flight resolution rather than
+// acquiring the container lock again. It must not be handed to another
+// goroutine while the BindFunc runs; once the call returns it behaves like
+// the container itself.
 type BindFunc func(*App) interface{}
 
-// Package-level globals for the default and named container instances.
-var (
-	defaultApp AppInterface
-	instances  map[string]AppInterface
-	mu         sync.RWMutex
-)
+// InterfaceBindFunc is a BindFunc receiving the container as an
+// AppInterface, for factories written against the interface. Accepted
+// anywhere a BindFunc is.
+type InterfaceBindFunc func(AppInterface) interface{}
 
-// App is the main DI container. It holds a binding registry, a contextual
-// injection registry (When/Needs/Give), and uses a reentrant mutex so
-// that BindFunc callbacks can safely call Make on the same container.
+// App is the main DI container. It holds a binding registry and a contextual
+// injection registry (When/Needs/Give). BindFunc callbacks receive a
+// per-call resolver App sharing the same container, so that nested Make
+// calls join the resolution that triggered them.
 type App struct {
+	*container
+	res     *resolution       // non-nil on a resolver handed out during a call
+	loading *deferredProvider // set on the resolver loading a deferred provider
+}
+
+// container is the state shared by an App and every resolver derived from it.
+type container struct {
+	self           *App // the App returned by New/Default, injected by di tags
+	parent         *App // set on scopes, consulted when a lookup misses
 	objectBuilder  ObjectInterface
 	typeChecker    TypeCheckerInterface
+	env            EnvSource
+	unexported     bool                       // AppConfig.InjectUnexported
+	autoBind       bool                       // AppConfig.AutoBind
+	catalogue      []reflect.Type             // Register, guarded by appMu
+	config         ConfigSource               // guarded by appMu
+	converters     map[reflect.Type]Converter // guarded by appMu
 	registry       map[string]ObjectInterface
-	injectRegistry map[string]map[string]ObjectInterface
-	resolving      map[string]bool // circular dependency detection during resolution
-	appMu          sync.Mutex
-	lockOwner      int64 // goroutine ID of current lock holder (atomic)
-	lockDepth      int32 // reentrant lock depth
+	defaults       map[string]ObjectInterface            // BindDefault, used when registry has no entry
+	injectRegistry map[string]map[string]ObjectInterface // inner maps are copy-on-write
+	requesters     []reflect.Type                        // interfaces given When rules, copy-on-write
+	providers      []ServiceProvider                     // RegisterProvider, guarded by appMu
+	deferred       map[string]*deferredProvider          // deferred providers by key, guarded by appMu
+	registered     int                                   // providers registered by Boot, guarded by bootMu
+	booted         int                                   // providers booted by Boot, guarded by bootMu
+	bootMu         sync.Mutex                            // serialises Boot
+	frozen         atomic.Bool                           // set by Freeze, the maps no longer change
+	transitive     atomic.Bool                           // a Within() rule was given
+	pending        atomic.Int32                          // deferred providers registered here and not loaded yet
+	appMu          sync.RWMutex                          // guards registry and injectRegistry
+}
+
+// resolution is the state of a single top-level call into the container.
+type resolution struct {
+	ctx       context.Context
+	resolving map[string]bool // circular dependency detection during resolution
+	path      []reflect.Type  // types being resolved, outermost first, for Within() rules
+	done      atomic.Bool     // set once the call that created it has returned
 }
 
 // AppConfig provides options when creating a new container via New().
 type AppConfig struct {
-	Name          string
-	ObjectBuilder ObjectInterface      // override for testing
-	TypeChecker   TypeCheckerInterface // override for testing
-	Default       bool
+	Name             string
+	ObjectBuilder    ObjectInterface      // override for testing
+	TypeChecker      TypeCheckerInterface // override for testing
+	Env              EnvSource            // source for env tags, defaults to the process environment
+	InjectUnexported bool                 // honour tags on unexported fields instead of skipping them
+	AutoBind         bool                 // resolve unbound interfaces to their one Register-ed implementation
+	Default          bool
+}
+
+// enter returns the resolver a public call should run against, plus the
+// function that ends it. A resolver still inside its call joins that
+// resolution; anything else starts a new one.
+func (A *App) enter() (*App, func()) {
+	return A.enterContext(nil)
 }
 
-// goroutineID extracts the current goroutine's ID from runtime.Stack output.
-func goroutineID() int64 {
-	var buf [64]byte
-	n := runtime.Stack(buf[:], false)
-	var id int64
-	for i := len("goroutine "); i < n && buf[i] != ' '; i++ {
-		id = id*10 + int64(buf[i]-'0')
+// enterContext is enter with a context for the call. A nil ctx inherits the
+// context of the resolution being joined, or context.Background().
+func (A *App) enterContext(ctx context.Context) (*App, func()) {
+	var resolving map[string]bool
+	var path []reflect.Type
+	if A.res != nil && !A.res.done.Load() {
+		if ctx == nil {
+			return A, func() {}
+		}
+		// Same resolution under a different context
+		resolving = A.res.resolving
+		path = A.res.path
+	} else {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		resolving = make(map[string]bool)
+	}
+
+	r := &App{container: A.container, res: &resolution{ctx: ctx, resolving: resolving, path: path}}
+	return r, func() {
+		r.res.done.Store(true)
 	}
-	return id
 }
 
-// lock acquires the container mutex. Reentrant: if the same goroutine already
-// holds it (e.g. a BindFunc calling Make), the depth counter increments instead
-// of deadlocking.
-func (A *App) lock() {
-	gid := goroutineID()
-	if atomic.LoadInt64(&A.lockOwner) == gid {
-		A.lockDepth++
-		return
+// registryOf and defaultsOf choose the map lookupIn searches: bindings,
+// or defaults from BindDefault, which only apply once neither a binding
+// nor a deferred provider exists for the key.
+func registryOf(c *container) map[string]ObjectInterface { return c.registry }
+
+func defaultsOf(c *container) map[string]ObjectInterface { return c.defaults }
+
+// lookupIn searches the map chosen by registry on A and then its parents.
+func (A *App) lookupIn(key string, registry func(*container) map[string]ObjectInterface) (ObjectInterface, bool) {
+	unlock := A.readLock()
+	o, e := registry(A.container)[key]
+	unlock()
+	if !e && A.parent != nil {
+		return A.parent.lookupIn(key, registry)
 	}
-	A.appMu.Lock()
-	atomic.StoreInt64(&A.lockOwner, gid)
-	A.lockDepth = 1
+	return o, e
+}
+
+// store registers o under key for op, replacing any existing binding.
+func (A *App) store(op string, key string, o ObjectInterface) {
+	defer A.writeLock(op)()
+	A.registry[key] = o
 }
 
-func (A *App) unlock() {
-	A.lockDepth--
-	if A.lockDepth == 0 {
-		atomic.StoreInt64(&A.lockOwner, 0)
-		A.appMu.Unlock()
+// hints returns the contextual bindings registered for the requesting type
+// key, falling back to the parent container for scopes. The returned map is
+// never written to and may be read without locking.
+func (A *App) hints(key string) (map[string]ObjectInterface, bool) {
+	unlock := A.readLock()
+	h, e := A.injectRegistry[key]
+	unlock()
+	if !e && A.parent != nil {
+		return A.parent.hints(key)
 	}
+	return h, e
 }
 
 // New creates a new App container instance with optional config.
@@ -96,87 +192,28 @@ func New(config ...AppConfig) *App {
 	return (&App{}).New(config...).(*App)
 }
 
-// Default returns the default or a named app instance, creating one if it doesn't exist.
+// Default returns the default or a named app instance, creating one if it
+// doesn't exist. It is GlobalRegistry().Default.
 func Default(name ...string) AppInterface {
-	if len(name) > 0 && len(name[0]) > 0 {
-		mu.RLock()
-		m := instances[name[0]]
-		mu.RUnlock()
-		if m == nil {
-			mu.Lock()
-			defer mu.Unlock()
-			if instances[name[0]] != nil {
-				return instances[name[0]]
-			}
-			if instances == nil {
-				instances = make(map[string]AppInterface)
-			}
-			instances[name[0]] = newAppInstance()
-			return instances[name[0]]
-		}
-
-		return m
-	}
-
-	mu.RLock()
-	if defaultApp != nil {
-		a := defaultApp
-		mu.RUnlock()
-		return a
-	}
-	mu.RUnlock()
-
-	mu.Lock()
-	defer mu.Unlock()
-	if defaultApp != nil {
-		return defaultApp
-	}
-	defaultApp = newAppInstance()
-	return defaultApp
+	return global.Default(name...)
 }
 
 func newAppInstance() *App {
-	a := new(App)
+	a := &App{container: new(container)}
+	a.self = a
 	a.objectBuilder = new(Object)
 	a.typeChecker = new(TypeChecker)
+	a.env = osEnv{}
 	a.registry = make(map[string]ObjectInterface)
+	a.defaults = make(map[string]ObjectInterface)
 	a.injectRegistry = make(map[string]map[string]ObjectInterface)
-	a.resolving = make(map[string]bool)
 	return a
 }
 
 // New creates a new container instance, optionally configured via AppConfig.
+// Named and default containers are registered in GlobalRegistry().
 func (A *App) New(config ...AppConfig) AppInterface {
-	a := newAppInstance()
-
-	mu.Lock()
-	if defaultApp == nil {
-		defaultApp = a
-	}
-
-	// Process config options - allows providing mocked objectBuilder & typeChecker for example
-	if len(config) > 0 {
-		c := config[0]
-		if len(c.Name) > 0 {
-			if instances == nil {
-				instances = make(map[string]AppInterface)
-			}
-
-			instances[c.Name] = a
-		}
-		if c.Default {
-			defaultApp = a
-		}
-		if c.ObjectBuilder != nil {
-			a.objectBuilder = c.ObjectBuilder
-		}
-		if c.TypeChecker != nil {
-			a.typeChecker = c.TypeChecker
-		}
-	}
-	mu.Unlock()
-
-	return a
+	return global.New(config...)
 }
 
 /*
@@ -211,20 +248,33 @@ Pointer - Singleton
 
 // Bind registers implementation b for type a. Pass nil as b to remove a binding.
 func (A *App) Bind(a interface{}, b interface{}) AppInterface {
-	A.lock()
-	defer A.unlock()
+	A.checkFrozen("Bind")
+	r, exit := A.enter()
+	defer exit()
+	r.bind(a, b)
+	return A
+}
 
+func (A *App) bind(a interface{}, b interface{}) {
+	if b == nil {
+		// Unset binding
+		A.deleteRegistryEntry("Bind", a)
+		return
+	}
+
+	// Bind label to object
+	label, o := A.newBinding(a, b)
+	A.store("Bind", label, o)
+}
+
+// newBinding validates a Bind of b to a and returns the registry label and
+// Object for it.
+func (A *App) newBinding(a interface{}, b interface{}) (string, ObjectInterface) {
 	var o ObjectInterface
 	var label string
 	var aType reflect.Type
 	var bType reflect.Type
 
-	if b == nil {
-		// Unset binding
-		A.deleteRegistryEntry(a)
-		return A
-	}
-
 	// Check that a & b are compatible binding
 	if !A.validBindCombination(a, b) {
 		if a != nil {
@@ -269,13 +319,26 @@ func (A *App) Bind(a interface{}, b interface{}) AppInterface {
 		panic(fmt.Sprintf("Unexpected error occurred, object not defined, inputs valid but didn't create object. Asked to bind %s to %s", bType, aType))
 	}
 
-	// Bind label to object
-	A.registry[label] = o
+	return label, o
+}
 
-	return A
+// singletonRecipe returns how to build v again for a Clone's singleton
+// bound under label. Once v's own type is bound to the singleton, resolving
+// it would find the singleton itself, so it is auto-generated instead.
+func (A *App) singletonRecipe(label string, v interface{}) func(*App) interface{} {
+	if A.typeFullName(reflect.TypeOf(v)) == label {
+		return func(r *App) interface{} { return r.autogen(v, make(map[string]interface{})) }
+	}
+	return func(r *App) interface{} { return r.makeInternal(v) }
 }
 
-func (A *App) deleteRegistryEntry(a interface{}) bool {
+func (A *App) deleteRegistryEntry(op string, a interface{}) bool {
+	return A.deleteEntry(op, registryOf, a)
+}
+
+// deleteEntry removes the binding for a, for op, from the map of the
+// container chosen by registry.
+func (A *App) deleteEntry(op string, registry func(*container) map[string]ObjectInterface, a interface{}) bool {
 	if a != nil {
 		// Unset binding
 		var label string
@@ -287,9 +350,12 @@ func (A *App) deleteRegistryEntry(a interface{}) bool {
 			label = A.typeFullName(aType)
 		}
 
-		if _, e := A.registry[label]; e {
-			delete(A.registry, label)
-			return true
+		defer A.writeLock(op)()
+		if m := registry(A.container); m != nil {
+			if _, e := m[label]; e {
+				delete(m, label)
+				return true
+			}
 		}
 	}
 
@@ -345,19 +411,32 @@ func (A *App) validBindCombination(a interface{}, b interface{}) (result bool) {
 
 // Singleton registers a shared instance for type a. Like Bind, but always returns the same instance.
 func (A *App) Singleton(a interface{}, c ...interface{}) AppInterface {
-	A.lock()
-	defer A.unlock()
-
-	var o ObjectInterface
-	var aType reflect.Type
-	var bType reflect.Type
+	A.checkFrozen("Singleton")
+	r, exit := A.enter()
+	defer exit()
+	r.singleton(a, c...)
+	return A
+}
 
+func (A *App) singleton(a interface{}, c ...interface{}) {
 	if len(c) == 1 && c[0] == nil {
 		// Unset binding
-		A.deleteRegistryEntry(a)
-		return A
+		A.deleteRegistryEntry("Singleton", a)
+		return
 	}
 
+	label, o := A.newSingleton(a, c...)
+	A.store("Singleton", label, o)
+}
+
+// newSingleton validates a Singleton of a, with c as for Singleton, and
+// returns the registry label and Object for it. Instances are built now.
+func (A *App) newSingleton(a interface{}, c ...interface{}) (string, ObjectInterface) {
+	var o ObjectInterface
+	var aType reflect.Type
+	var bType reflect.Type
+	var recreate func(*App) interface{}
+
 	// Check that a & optional b are compatible binding
 	if !A.validSingletonCombination(a, c...) {
 		if a != nil {
@@ -379,6 +458,7 @@ func (A *App) Singleton(a interface{}, c ...interface{}) AppInterface {
 		}
 
 		if reflect.ValueOf(a).IsNil() {
+			recreate = A.singletonRecipe(label, a)
 			a = A.makeInternal(a)
 		}
 
@@ -392,12 +472,14 @@ func (A *App) Singleton(a interface{}, c ...interface{}) AppInterface {
 		// Obtain result of BindFunc and bind to that
 		if bType.Kind() == reflect.Func {
 			bf := A.interfaceToBindFunc(b)
+			recreate = bf
 			b = bf(A)
 			realAType := A.resolveTypePtr(aType)
 			if !A.typeChecker.IsTypeCompatible(realAType, reflect.TypeOf(b), false) {
 				panic(fmt.Sprintf("Singleton BindFunc returned %s which is not compatible with %s", reflect.TypeOf(b), aType))
 			}
 		} else if isNilableKind(bType.Kind()) && reflect.ValueOf(b).IsNil() {
+			recreate = A.singletonRecipe(label, b)
 			b = A.makeInternal(b)
 		}
 
@@ -409,10 +491,11 @@ func (A *App) Singleton(a interface{}, c ...interface{}) AppInterface {
 	}
 
 	o.Singleton()
+	if x, ok := o.(*Object); ok {
+		x.recreate = recreate
+	}
 
-	A.registry[label] = o
-
-	return A
+	return label, o
 }
 
 // Defines valid singleton combinations
@@ -460,10 +543,11 @@ func (A *App) validSingletonCombination(a interface{}, c ...interface{}) (result
 	return false
 }
 
-func (A *App) When(a interface{}) *whenLink {
+func (A *App) When(a interface{}, more ...interface{}) WhenBuilder {
 	return &whenLink{
 		A,
-		a,
+		append([]interface{}{a}, more...),
+		false,
 	}
 }
 
@@ -471,9 +555,9 @@ func (A *App) When(a interface{}) *whenLink {
 // constructor methods (New), and struct tags (inject/di) to build the result.
 // Panics if a required interface or string binding is not found.
 func (A *App) Make(a interface{}) interface{} {
-	A.lock()
-	defer A.unlock()
-	return A.makeInternal(a)
+	r, exit := A.enter()
+	defer exit()
+	return r.makeInternal(a)
 }
 
 func (A *App) makeInternal(a interface{}) interface{} {
@@ -482,9 +566,9 @@ func (A *App) makeInternal(a interface{}) interface{} {
 
 // MakeWith resolves type a with per-call field overrides. Map keys are field names.
 func (A *App) MakeWith(a interface{}, injectables map[string]interface{}) interface{} {
-	A.lock()
-	defer A.unlock()
-	return A.makeWithInternal(a, injectables)
+	r, exit := A.enter()
+	defer exit()
+	return r.makeWithInternal(a, injectables)
 }
 
 func (A *App) makeWithInternal(a interface{}, injectables map[string]interface{}) interface{} {
@@ -503,13 +587,32 @@ func (A *App) makeWithInternal(a interface{}, injectables map[string]interface{}
 		resolveKey = A.typeFullName(t)
 	}
 
-	if A.resolving[resolveKey] {
+	A.checkContext(resolveKey)
+
+	// Bindings, then deferred providers, then defaults
+	o, found := A.withinHint(resolveKey)
+	if !found {
+		o, found = A.lookupIn(resolveKey, registryOf)
+	}
+	if !found && A.loadDeferred(resolveKey) {
+		o, found = A.lookupIn(resolveKey, registryOf)
+	}
+	if !found {
+		o, _ = A.lookupIn(resolveKey, defaultsOf)
+	}
+
+	resolving := A.res.resolving
+	if resolving[resolveKey] {
 		panic(fmt.Sprintf("circular dependency detected while resolving %s", resolveKey))
 	}
-	A.resolving[resolveKey] = true
-	defer delete(A.resolving, resolveKey)
+	resolving[resolveKey] = true
+	defer delete(resolving, resolveKey)
+	if t.Kind() != reflect.String {
+		A.res.path = append(A.res.path, t)
+		defer func(n int) { A.res.path = A.res.path[:n] }(len(A.res.path) - 1)
+	}
 
-	x, e = A.registry[resolveKey].(*Object)
+	x, e = o.(*Object)
 
 	if e {
 		result := A.processObject(x, injectables)
@@ -523,10 +626,22 @@ func (A *App) makeWithInternal(a interface{}, injectables map[string]interface{}
 		return result
 	}
 
+	if resolveKey == contextKey {
+		// Unbound context.Context resolves to the resolution's context
+		return A.Context()
+	}
+	if resolveKey == configSourceKey && A.configSource() != nil {
+		// Unbound ConfigSource resolves to the configuration from BindConfig
+		return A.configSource()
+	}
 	if t.Kind() == reflect.String {
 		panic(fmt.Sprintf("no binding found for %s", a))
 	}
-	if A.resolveTypePtr(t).Kind() == reflect.Interface {
+	if it := A.resolveTypePtr(t); it.Kind() == reflect.Interface {
+		if impl, ok := A.discover(it); ok {
+			// AutoBind: the one registered type implementing it
+			return A.makeWithInternal(impl, injectables)
+		}
 		panic(fmt.Sprintf("no binding found for %s", t))
 	}
 
@@ -541,6 +656,16 @@ func (A *App) processObject(x *Object, injectables map[string]interface{}) inter
 		return A.makeWithInternal(x.Value, injectables)
 	}
 	if x.IsSingleton() {
+		if x.Kind == Func {
+			// Contextual singleton given a BindFunc, built on first use
+			return x.instance(func() interface{} {
+				return A.processStructTags(A.interfaceToBindFunc(x.Value)(A), injectables)
+			})
+		}
+		if x.build != nil {
+			// Singleton re-created by a Clone, built on first use
+			return x.instance(func() interface{} { return x.build(A) })
+		}
 		return x.Value
 	}
 
@@ -589,21 +714,54 @@ func (A *App) makeByHints(a interface{}, injectables map[string]interface{}) int
 	}
 
 	// Use injection registry - if x needs y give z
-	hintmap, hasmap := A.injectRegistry[A.typeFullName(ot)]
+	hintmap, hasmap := A.hintsFor(ot)
+
+	A.injectByHints(newobj.Elem(), hintmap, hasmap, injectables, nil)
+
+	// Convert Ptr to Struct if requested
+	if ot.Kind() == reflect.Struct {
+		return newobj.Elem().Interface()
+	}
+
+	return newobj.Interface()
+}
+
+// injectByHints fills the tagged fields of the addressable struct v for
+// makeByHints, recursing into embedded structs. outer lists the structs
+// that embed v.
+func (A *App) injectByHints(v reflect.Value, hintmap map[string]ObjectInterface, hasmap bool, injectables map[string]interface{}, outer []reflect.Type) {
+	t := v.Type()
+	outer = append(outer, t)
 
 	// Iterate over the fields of the struct
 	for fn := 0; fn < t.NumField(); fn++ {
 		f := t.Field(fn)
+		if embedded, ok := A.embeddedStruct(f, v.Field(fn), outer); ok {
+			if embedded.IsValid() {
+				A.injectByHints(embedded, hintmap, hasmap, injectables, outer)
+			}
+			continue
+		}
 		// Obtain tag inject values
 		injectValue, inject := f.Tag.Lookup("inject")
+		injectValue, injectOpts := parseInjectTag(injectValue)
 		_, di := f.Tag.Lookup("di")
-		newField := newobj.Elem().Field(fn)
-		if newField.CanSet() {
-			if di && inject && injectValue != "" && isPrimitiveKind(f.Type.Kind()) {
-				A.setByTagValue(f.Type.Kind(), newField, injectValue)
+		envValue, env := f.Tag.Lookup("env")
+		configValue, config := f.Tag.Lookup("config")
+		newField, settable := A.settableField(v.Field(fn))
+		if settable {
+			if env {
+				A.setByEnvTag(f, newField, envValue, injectables)
+			} else if config {
+				A.setByConfigTag(f, newField, configValue, injectables)
+			} else if hv, ok := A.fieldHint(f, hintmap, injectables); ok {
+				newField.Set(hv)
+			} else if di && inject && injectValue != "" && A.isLiteralType(f.Type) {
+				A.setByTagValue(newField, injectValue)
 			} else if di {
-				containerVal := reflect.ValueOf(A)
-				if containerVal.Type().AssignableTo(f.Type) {
+				containerVal := reflect.ValueOf(A.self)
+				// The container, unless an implementation is bound for the field's interface
+				if containerVal.Type().AssignableTo(f.Type) && !A.isBound(f.Type) {
 					newField.Set(containerVal)
 				} else {
 					var c interface{}
@@ -643,7 +801,13 @@ func (A *App) makeByHints(a interface{}, injectables map[string]interface{}) int
 					newField.Set(reflect.ValueOf(c))
 				} else if injectValue != "" {
 					// Inject value provided
-					A.setByTagValue(f.Type.Kind(), newField, injectValue)
+					A.setByTagValue(newField, injectValue)
+				} else if isOptionalType(f.Type) {
+					newField.Set(reflect.ValueOf(A.makeOptional(f.Type, hintmap)))
+				} else if isProviderType(f.Type) {
+					newField.Set(A.makeProvider(f.Type, hintmap))
+				} else if injectOpts.optional && !A.isBound(f.Type) {
+					// Nothing bound for an optional field, leave it zero
 				} else {
 					// Call Make on compatible field types
 					var c interface{}
@@ -670,13 +834,6 @@ func (A *App) makeByHints(a interface{}, injectables map[string]interface{}) int
 			}
 		}
 	}
-
-	// Convert Ptr to Struct if requested
-	if ot.Kind() == reflect.Struct {
-		return newobj.Elem().Interface()
-	}
-
-	return newobj.Interface()
 }
 
 // makeByNew calls the type's New() constructor method with auto-resolved
@@ -693,7 +850,7 @@ func (A *App) makeByNew(a interface{}, injectables map[string]interface{}) inter
 	}
 
 	// Obtain preset injection map for object
-	hintmap, hasmap := A.injectRegistry[A.typeFullName(t)]
+	hintmap, hasmap := A.hintsFor(t)
 
 	method, _ := t.MethodByName("New")
 
@@ -705,6 +862,19 @@ func (A *App) makeByNew(a interface{}, injectables map[string]interface{}) inter
 
 		childType := method.Type.In(v)
 
+		if hv, ok := A.namedHint(hintmap, strconv.Itoa(v-1), childType); ok {
+			injects = append(injects, hv)
+			continue
+		}
+		if isOptionalType(childType) {
+			injects = append(injects, reflect.ValueOf(A.makeOptional(childType, hintmap)))
+			continue
+		}
+		if isProviderType(childType) {
+			injects = append(injects, A.makeProvider(childType, hintmap))
+			continue
+		}
+
 		var pPtr reflect.Value
 
 		if childType.Kind() == reflect.Ptr {
@@ -803,23 +973,54 @@ func (A *App) processStructTags(a interface{}, injectables map[string]interface{
 		val = v.Elem()
 	}
 
-	hintmap, hasmap := A.injectRegistry[A.typeFullName(ot)]
+	hintmap, hasmap := A.hintsFor(ot)
+
+	A.injectByTags(val, hintmap, hasmap, injectables, nil)
+
+	if ot.Kind() == reflect.Struct {
+		return val.Interface()
+	}
+	return val.Addr().Interface()
+}
+
+// injectByTags fills the tagged fields of the addressable struct val for
+// processStructTags, recursing into embedded structs. outer lists the
+// structs that embed val.
+func (A *App) injectByTags(val reflect.Value, hintmap map[string]ObjectInterface, hasmap bool, injectables map[string]interface{}, outer []reflect.Type) {
+	t := val.Type()
+	outer = append(outer, t)
 
 	for fn := 0; fn < t.NumField(); fn++ {
 		f := t.Field(fn)
-		fieldVal := val.Field(fn)
-		if !fieldVal.CanSet() {
+		if embedded, ok := A.embeddedStruct(f, val.Field(fn), outer); ok {
+			if embedded.IsValid() {
+				A.injectByTags(embedded, hintmap, hasmap, injectables, outer)
+			}
+			continue
+		}
+		fieldVal, settable := A.settableField(val.Field(fn))
+		if !settable {
 			continue
 		}
 
 		_, di := f.Tag.Lookup("di")
 		injectValue, inject := f.Tag.Lookup("inject")
-
-		if di && inject && injectValue != "" && isPrimitiveKind(f.Type.Kind()) {
-			A.setByTagValue(f.Type.Kind(), fieldVal, injectValue)
+		injectValue, injectOpts := parseInjectTag(injectValue)
+		envValue, env := f.Tag.Lookup("env")
+		configValue, config := f.Tag.Lookup("config")
+
+		if env {
+			A.setByEnvTag(f, fieldVal, envValue, injectables)
+		} else if config {
+			A.setByConfigTag(f, fieldVal, configValue, injectables)
+		} else if hv, ok := A.fieldHint(f, hintmap, injectables); ok {
+			fieldVal.Set(hv)
+		} else if di && inject && injectValue != "" && A.isLiteralType(f.Type) {
+			A.setByTagValue(fieldVal, injectValue)
 		} else if di && fieldVal.IsZero() {
-			containerVal := reflect.ValueOf(A)
-			if containerVal.Type().AssignableTo(f.Type) {
+			containerVal := reflect.ValueOf(A.self)
+			// The container, unless an implementation is bound for the field's interface
+			if containerVal.Type().AssignableTo(f.Type) && !A.isBound(f.Type) {
 				fieldVal.Set(containerVal)
 			} else {
 				var c interface{}
@@ -855,7 +1056,13 @@ func (A *App) processStructTags(a interface{}, injectables map[string]interface{
 				c := A.processObject(po.(*Object), make(map[string]interface{}))
 				fieldVal.Set(reflect.ValueOf(c))
 			} else if injectValue != "" {
-				A.setByTagValue(f.Type.Kind(), fieldVal, injectValue)
+				A.setByTagValue(fieldVal, injectValue)
+			} else if isOptionalType(f.Type) {
+				fieldVal.Set(reflect.ValueOf(A.makeOptional(f.Type, hintmap)))
+			} else if isProviderType(f.Type) {
+				fieldVal.Set(A.makeProvider(f.Type, hintmap))
+			} else if injectOpts.optional && !A.isBound(f.Type) {
+				// Nothing bound for an optional field, keep its current value
 			} else if f.Type.Kind() == reflect.Ptr || f.Type.Kind() == reflect.Struct || f.Type.Kind() == reflect.Interface {
 				var pPtr reflect.Value
 				if f.Type.Kind() == reflect.Ptr {
@@ -872,125 +1079,29 @@ func (A *App) processStructTags(a interface{}, injectables map[string]interface{
 			}
 		}
 	}
-
-	if ot.Kind() == reflect.Struct {
-		return val.Interface()
-	}
-	return val.Addr().Interface()
 }
 
-func (A *App) setByTagValue(k reflect.Kind, f reflect.Value, v string) {
-	// Parsing for inject values on primitives
+func isIntKind(k reflect.Kind) bool {
 	switch k {
-	case reflect.String:
-		f.Set(reflect.ValueOf(v))
-		return
-	case reflect.Bool:
-		iv, err := strconv.ParseBool(v)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(iv))
-		return
-	case reflect.Float32:
-		iv, err := strconv.ParseFloat(v, 32)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(float32(iv)))
-		return
-	case reflect.Float64:
-		iv, err := strconv.ParseFloat(v, 64)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(iv))
-		return
-	case reflect.Int:
-		iv, err := strconv.Atoi(v)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(iv))
-		return
-	case reflect.Int8:
-		iv, err := strconv.ParseInt(v, 10, 8)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(int8(iv)))
-		return
-	case reflect.Int16:
-		iv, err := strconv.ParseInt(v, 10, 16)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(int16(iv)))
-		return
-	case reflect.Int32:
-		iv, err := strconv.ParseInt(v, 10, 32)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(int32(iv)))
-		return
-	case reflect.Int64:
-		iv, err := strconv.ParseInt(v, 10, 64)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(iv))
-		return
-	case reflect.Uint:
-		iv, err := strconv.ParseUint(v, 10, 64)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(uint(iv)))
-		return
-	case reflect.Uint8:
-		iv, err := strconv.ParseUint(v, 10, 8)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(uint8(iv)))
-		return
-	case reflect.Uint16:
-		iv, err := strconv.ParseUint(v, 10, 16)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(uint16(iv)))
-		return
-	case reflect.Uint32:
-		iv, err := strconv.ParseUint(v, 10, 32)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(uint32(iv)))
-		return
-	case reflect.Uint64:
-		iv, err := strconv.ParseUint(v, 10, 64)
-		if err != nil {
-			panic(err)
-		}
-		f.Set(reflect.ValueOf(iv))
-		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
 	}
-
-	// Can not handle parsing for this type
-	panic(fmt.Sprintf("can not initialize value for kind %s", k))
+	return false
 }
 
-func isIntKind(k reflect.Kind) bool {
+func isSignedKind(k reflect.Kind) bool {
 	switch k {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return true
 	}
 	return false
 }
 
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
 func isPrimitiveKind(k reflect.Kind) bool {
 	switch k {
 	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
@@ -1028,9 +1139,26 @@ func resolveTypePtr(t reflect.Type) reflect.Type {
 	return t
 }
 
-// Converts an interface into a BindFunc
+// Converts an interface into a BindFunc. A ContextBindFunc is wrapped to run
+// with the resolver's context and panic with any error it returns.
 func (A *App) interfaceToBindFunc(a interface{}) BindFunc {
 	aType := reflect.TypeOf(a)
+	if aType.ConvertibleTo(interfaceBindFuncType) {
+		ibf := reflect.ValueOf(a).Convert(interfaceBindFuncType).Interface().(InterfaceBindFunc)
+		return func(r *App) interface{} {
+			return ibf(r)
+		}
+	}
+	if aType.ConvertibleTo(contextBindFuncType) {
+		cbf := reflect.ValueOf(a).Convert(contextBindFuncType).Interface().(ContextBindFunc)
+		return func(r *App) interface{} {
+			v, err := cbf(r.Context(), r)
+			if err != nil {
+				panic(err)
+			}
+			return v
+		}
+	}
 	if !aType.ConvertibleTo(bindFuncType) {
 		panic("Unsupported function type, must be compatible with BindFunc")
 	}
@@ -1038,21 +1166,31 @@ func (A *App) interfaceToBindFunc(a interface{}) BindFunc {
 }
 
 var (
-	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
-	appPtrType         = reflect.TypeOf((*App)(nil))
-	bindFuncType       = reflect.TypeOf(BindFunc(nil))
+	emptyInterfaceType    = reflect.TypeOf((*interface{})(nil)).Elem()
+	appPtrType            = reflect.TypeOf((*App)(nil))
+	appInterfaceType      = reflect.TypeOf((*AppInterface)(nil)).Elem()
+	bindFuncType          = reflect.TypeOf(BindFunc(nil))
+	interfaceBindFuncType = reflect.TypeOf(InterfaceBindFunc(nil))
 )
 
-// Checks if a BindFunc's signature is compatible (takes *App, returns one compatible value)
+// Checks if a BindFunc's signature is compatible (takes *App or AppInterface,
+// returns one compatible value) or a ContextBindFunc's (takes context.Context and *App,
+// returns a compatible value and an error)
 func (A *App) isFuncSignatureCompatible(b interface{}, t reflect.Type) bool {
 	bType := reflect.TypeOf(b)
 	if bType.Kind() != reflect.Func {
 		return false
 	}
-	if bType.NumIn() != 1 || bType.In(0) != appPtrType {
-		return false
-	}
-	if bType.NumOut() != 1 {
+	switch {
+	case bType.NumIn() == 1 && (bType.In(0) == appPtrType || bType.In(0) == appInterfaceType):
+		if bType.NumOut() != 1 {
+			return false
+		}
+	case bType.NumIn() == 2 && bType.In(0) == contextType && bType.In(1) == appPtrType:
+		if bType.NumOut() != 2 || bType.Out(1) != errorType {
+			return false
+		}
+	default:
 		return false
 	}
 	outType := bType.Out(0)
@@ -0,0 +1,181 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Snapshot is a copy of a container's bindings, taken by App.Snapshot and
+// put back by App.Restore. Bound objects, and so singleton instances, are
+// shared with the container rather than copied.
+type Snapshot struct {
+	registry       map[string]ObjectInterface
+	defaults       map[string]ObjectInterface
+	injectRegistry map[string]map[string]ObjectInterface
+	requesters     []reflect.Type
+	transitive     bool
+}
+
+// CloneConfig provides options for App.Clone.
+type CloneConfig struct {
+	// FreshSingletons gives the clone its own instances of singletons the
+	// container built, from a BindFunc or by auto-generation. They are
+	// re-created on first use in the clone. Instances passed to Singleton
+	// directly are always shared.
+	FreshSingletons bool
+}
+
+// Snapshot copies the bindings, defaults and contextual rules registered
+// directly on A.
+func (A *App) Snapshot() *Snapshot {
+	unlock := A.readLock()
+	defer unlock()
+
+	return &Snapshot{
+		registry:       copyRegistry(A.registry),
+		defaults:       copyRegistry(A.defaults),
+		injectRegistry: copyInjectRegistry(A.injectRegistry),
+		requesters:     A.requesters,
+		transitive:     A.transitive.Load(),
+	}
+}
+
+// Restore replaces the bindings, defaults and contextual rules registered
+// directly on A with those in s. A snapshot can be restored any number of
+// times, also onto a different container.
+func (A *App) Restore(s *Snapshot) AppInterface {
+	A.checkFrozen("Restore")
+	if s == nil {
+		panic("Restore() requires a non-nil Snapshot")
+	}
+
+	defer A.writeLock("Restore")()
+	A.registry = copyRegistry(s.registry)
+	A.defaults = copyRegistry(s.defaults)
+	A.injectRegistry = copyInjectRegistry(s.injectRegistry)
+	A.requesters = s.requesters
+	A.transitive.Store(s.transitive)
+
+	return A
+}
+
+// Clone returns an independent container with A's bindings, contextual
+// rules, configuration, converters, registered types and providers, so a
+// bootstrapped container can be forked without running its setup again.
+// Changes to either afterwards do not affect the other. Singletons are
+// shared unless CloneConfig.FreshSingletons is set. A clone of a scope has
+// the same parent, and a clone is never frozen nor registered as a default
+// or named instance.
+func (A *App) Clone(config ...CloneConfig) AppInterface {
+	var cc CloneConfig
+	if len(config) > 0 {
+		cc = config[0]
+	}
+
+	c := newAppInstance()
+	c.parent = A.parent
+	c.objectBuilder = A.objectBuilder
+	c.typeChecker = A.typeChecker
+	c.env = A.env
+	c.unexported = A.unexported
+	c.autoBind = A.autoBind
+
+	A.bootMu.Lock()
+	c.registered, c.booted = A.registered, A.booted
+	A.bootMu.Unlock()
+
+	unlock := A.readLock()
+	defer unlock()
+
+	c.config = A.config
+	for t, conv := range A.converters {
+		if c.converters == nil {
+			c.converters = make(map[reflect.Type]Converter)
+		}
+		c.converters[t] = conv
+	}
+	c.catalogue = append([]reflect.Type(nil), A.catalogue...)
+	c.providers = append([]ServiceProvider(nil), A.providers...)
+
+	// Providers not loaded yet are loaded separately by each container
+	pending := make(map[*deferredProvider]*deferredProvider)
+	for k, d := range A.deferred {
+		if d.loaded.Load() && d.failure == nil {
+			continue
+		}
+		if c.deferred == nil {
+			c.deferred = make(map[string]*deferredProvider)
+		}
+		if d.loaded.Load() {
+			// A failed provider keeps failing in the clone
+			c.deferred[k] = d
+			continue
+		}
+		if pending[d] == nil {
+			pending[d] = &deferredProvider{p: d.p, keys: d.keys}
+		}
+		c.deferred[k] = pending[d]
+	}
+	c.pending.Store(int32(len(pending)))
+
+	c.registry = copyRegistry(A.registry)
+	c.defaults = copyRegistry(A.defaults)
+	c.injectRegistry = copyInjectRegistry(A.injectRegistry)
+	c.requesters = A.requesters
+	c.transitive.Store(A.transitive.Load())
+	if cc.FreshSingletons {
+		freshSingletons(c.registry)
+		freshSingletons(c.defaults)
+		for w, hints := range c.injectRegistry {
+			hints = copyRegistry(hints)
+			freshSingletons(hints)
+			c.injectRegistry[w] = hints
+		}
+	}
+
+	return c
+}
+
+// freshSingletons replaces the singletons in registry that the container
+// built with copies that build their own instance on first use.
+func freshSingletons(registry map[string]ObjectInterface) {
+	for k, o := range registry {
+		x, ok := o.(*Object)
+		if ok && x.perType != nil {
+			// Per-requester instances are built again on first use
+			fresh := *x
+			fresh.perType = new(sync.Map)
+			registry[k] = &fresh
+			continue
+		}
+		if !ok || !x.IsSingleton() || (x.Kind != Func && x.recreate == nil) {
+			continue
+		}
+		fresh := *x
+		fresh.shared = new(sharedInstance)
+		if x.Kind != Func {
+			// Built again on first use rather than copied
+			fresh.Value = nil
+			fresh.build = x.recreate
+		}
+		registry[k] = &fresh
+	}
+}
+
+func copyRegistry(registry map[string]ObjectInterface) map[string]ObjectInterface {
+	c := make(map[string]ObjectInterface, len(registry))
+	for k, o := range registry {
+		c[k] = o
+	}
+	return c
+}
+
+// copyInjectRegistry copies the outer map only: the hint maps inside are
+// copy-on-write and can be shared.
+func copyInjectRegistry(injectRegistry map[string]map[string]ObjectInterface) map[string]map[string]ObjectInterface {
+	c := make(map[string]map[string]ObjectInterface, len(injectRegistry))
+	for k, hints := range injectRegistry {
+		c[k] = hints
+	}
+	return c
+}
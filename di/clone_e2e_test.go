@@ -0,0 +1,140 @@
+package di
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+type CloneStore interface {
+	ID() int64
+}
+
+type CloneCountingStore struct {
+	id int64
+}
+
+func (c *CloneCountingStore) ID() int64 { return c.id }
+
+type CloneAlt struct{}
+
+func (c *CloneAlt) ID() int64 { return -1 }
+
+type CloneConsumer struct {
+	Store CloneStore `inject:""`
+}
+
+type CloneAuto struct {
+	Name string `inject:"auto"`
+}
+
+func newCloneApp(built *atomic.Int64) *App {
+	c := New()
+	c.Singleton((*CloneStore)(nil), func(a *App) interface{} {
+		return &CloneCountingStore{id: built.Add(1)}
+	})
+	c.Singleton((*CloneAuto)(nil))
+	return c
+}
+
+func TestSnapshot_RestoreUndoesChanges(t *testing.T) {
+	var built atomic.Int64
+	c := newCloneApp(&built)
+	c.When(&CloneConsumer{}).Needs((*CloneStore)(nil)).Give(&CloneAlt{})
+	snap := c.Snapshot()
+
+	c.Bind((*CloneStore)(nil), &CloneAlt{})
+	c.When(&CloneConsumer{}).Needs((*CloneStore)(nil)).Give(nil)
+	c.Bind("extra", &CloneAlt{})
+
+	c.Restore(snap)
+	if c.Make((*CloneStore)(nil)).(CloneStore).ID() != 1 {
+		t.Error("Expected the original singleton after Restore")
+	}
+	if c.Make(&CloneConsumer{}).(*CloneConsumer).Store.ID() != -1 {
+		t.Error("Expected the contextual rule to be restored")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected the binding added after the snapshot to be gone")
+		}
+	}()
+	c.Make("extra")
+}
+
+func TestSnapshot_RestoreTwice(t *testing.T) {
+	var built atomic.Int64
+	c := newCloneApp(&built)
+	snap := c.Snapshot()
+
+	for i := 0; i < 2; i++ {
+		c.Bind((*CloneStore)(nil), &CloneAlt{})
+		c.Restore(snap)
+		if c.Make((*CloneStore)(nil)).(CloneStore).ID() != 1 {
+			t.Errorf("Restore %d: expected the snapshotted binding", i)
+		}
+	}
+}
+
+func TestClone_SharesSingletonsAndIsIndependent(t *testing.T) {
+	var built atomic.Int64
+	c := newCloneApp(&built)
+	clone := c.Clone()
+
+	if clone.Make((*CloneStore)(nil)) != c.Make((*CloneStore)(nil)) {
+		t.Error("Expected singletons to be shared by default")
+	}
+
+	clone.Bind((*CloneStore)(nil), &CloneAlt{})
+	if c.Make((*CloneStore)(nil)).(CloneStore).ID() != 1 {
+		t.Error("Expected bindings on the clone not to affect the original")
+	}
+	c.When(&CloneConsumer{}).Needs((*CloneStore)(nil)).Give(&CloneCountingStore{id: 9})
+	if clone.Make(&CloneConsumer{}).(*CloneConsumer).Store.ID() != -1 {
+		t.Error("Expected rules on the original not to affect the clone")
+	}
+}
+
+func TestClone_FreshSingletons(t *testing.T) {
+	var built atomic.Int64
+	c := newCloneApp(&built)
+	clone := c.Clone(CloneConfig{FreshSingletons: true})
+
+	if built.Load() != 1 {
+		t.Fatal("Expected Clone not to build singletons eagerly")
+	}
+
+	a := clone.Make((*CloneStore)(nil)).(CloneStore)
+	if a.ID() != 2 || clone.Make((*CloneStore)(nil)) != a {
+		t.Error("Expected the clone to build its own singleton once")
+	}
+	if c.Make((*CloneStore)(nil)).(CloneStore).ID() != 1 {
+		t.Error("Expected the original's singleton to be untouched")
+	}
+	if clone.Make((*CloneAuto)(nil)) == c.Make((*CloneAuto)(nil)) {
+		t.Error("Expected auto-generated singletons to be re-created too")
+	}
+}
+
+func TestClone_GivenInstanceAlwaysShared(t *testing.T) {
+	c := New()
+	store := &CloneCountingStore{id: 5}
+	c.Singleton((*CloneStore)(nil), store)
+
+	clone := c.Clone(CloneConfig{FreshSingletons: true})
+	if clone.Make((*CloneStore)(nil)) != store {
+		t.Error("Expected an instance passed to Singleton to be shared")
+	}
+}
+
+func TestClone_OfFrozenIsWritable(t *testing.T) {
+	var built atomic.Int64
+	c := newCloneApp(&built)
+	c.Freeze()
+
+	clone := c.Clone()
+	clone.Bind((*CloneStore)(nil), &CloneAlt{})
+	if clone.Make((*CloneStore)(nil)).(CloneStore).ID() != -1 {
+		t.Error("Expected the clone of a frozen container to accept bindings")
+	}
+}
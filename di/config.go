@@ -0,0 +1,314 @@
+package di
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ConfigSource supplies configuration values by dotted key, e.g.
+// "database.pool.max". Values are JSON-like: nested map[string]interface{}
+// and []interface{} trees of strings, numbers and bools.
+type ConfigSource interface {
+	Lookup(key string) (interface{}, bool)
+}
+
+// ConfigMap is a ConfigSource backed by a nested map, as decoded from JSON.
+// Key segments match map keys exactly, or failing that case-insensitively.
+type ConfigMap map[string]interface{}
+
+// Lookup walks the map one dotted key segment at a time.
+func (c ConfigMap) Lookup(key string) (interface{}, bool) {
+	var v interface{} = map[string]interface{}(c)
+	for _, segment := range strings.Split(key, ".") {
+		m, ok := asConfigTree(v)
+		if !ok {
+			return nil, false
+		}
+		v, ok = lookupSegment(m, segment)
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// JSONConfig loads a JSON object from the file at path.
+func JSONConfig(path string) (ConfigMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var c ConfigMap
+	if err := d.Decode(&c); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ConfigEnv is a ConfigSource reading environment variables: key
+// "database.pool.max" is read from PREFIX_DATABASE_POOL_MAX, or
+// DATABASE_POOL_MAX when Prefix is empty. A nil Env reads the process
+// environment.
+type ConfigEnv struct {
+	Prefix string
+	Env    EnvSource
+}
+
+// Lookup returns the variable for key as a string.
+func (c ConfigEnv) Lookup(key string) (interface{}, bool) {
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if c.Prefix != "" {
+		name = c.Prefix + "_" + name
+	}
+
+	env := c.Env
+	if env == nil {
+		env = osEnv{}
+	}
+	v, ok := env.LookupEnv(name)
+	if !ok {
+		return nil, false
+	}
+	return v, true
+}
+
+// layeredConfig is the ConfigSource returned by Layered.
+type layeredConfig []ConfigSource
+
+// Layered combines sources so that later ones override earlier ones, e.g.
+// Layered(defaults, file, ConfigEnv{}). Maps found in several layers are
+// merged key by key.
+func Layered(sources ...ConfigSource) ConfigSource {
+	return layeredConfig(sources)
+}
+
+func (l layeredConfig) Lookup(key string) (interface{}, bool) {
+	var result interface{}
+	found := false
+	for _, s := range l {
+		v, ok := s.Lookup(key)
+		if !ok {
+			continue
+		}
+		if found {
+			result = mergeConfig(result, v)
+		} else {
+			result = v
+			found = true
+		}
+	}
+	return result, found
+}
+
+// mergeConfig overlays b onto a when both are maps, otherwise b wins.
+func mergeConfig(a, b interface{}) interface{} {
+	am, aok := asConfigTree(a)
+	bm, bok := asConfigTree(b)
+	if !aok || !bok {
+		return b
+	}
+
+	m := make(map[string]interface{}, len(am)+len(bm))
+	for k, v := range am {
+		m[k] = v
+	}
+	for k, v := range bm {
+		if existing, ok := m[k]; ok {
+			v = mergeConfig(existing, v)
+		}
+		m[k] = v
+	}
+	return m
+}
+
+func asConfigTree(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case ConfigMap:
+		return m, true
+	}
+	return nil, false
+}
+
+func lookupSegment(m map[string]interface{}, segment string) (interface{}, bool) {
+	if v, ok := m[segment]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, segment) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+var configSourceKey = typeFullName(reflect.TypeOf((*ConfigSource)(nil)))
+
+// BindConfig registers the configuration read by config tags. Several
+// sources are layered with later ones taking precedence. Scopes use their
+// parent's configuration unless given their own.
+func (A *App) BindConfig(sources ...ConfigSource) AppInterface {
+	A.checkFrozen("BindConfig")
+	var c ConfigSource
+	switch len(sources) {
+	case 0:
+		panic("BindConfig() requires at least one ConfigSource")
+	case 1:
+		c = sources[0]
+	default:
+		c = Layered(sources...)
+	}
+
+	unlock := A.writeLock("BindConfig")
+	A.config = c
+	unlock()
+
+	return A
+}
+
+// configSource returns the bound configuration, falling back to the parent
+// container for scopes.
+func (A *App) configSource() ConfigSource {
+	unlock := A.readLock()
+	c := A.config
+	unlock()
+	if c == nil && A.parent != nil {
+		return A.parent.configSource()
+	}
+	return c
+}
+
+// setByConfigTag sets field f from the configuration value under key. A
+// MakeWith value for the field takes precedence. Missing keys panic.
+func (A *App) setByConfigTag(f reflect.StructField, field reflect.Value, key string, injectables map[string]interface{}) {
+	pv, pe := injectables[f.Name]
+	if pe && pv != nil && reflect.ValueOf(pv).Type().AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(pv))
+		return
+	}
+
+	c := A.configSource()
+	if c == nil {
+		panic(fmt.Sprintf("no configuration bound, needed for %s (field %s)", key, f.Name))
+	}
+	A.setByConfig(c, field, key)
+}
+
+// setByConfig converts the value under key into field, recursing into
+// slices, maps and structs. Nested struct fields are looked up under
+// key + "." + their config tag, or their name when untagged, so that every
+// layer of a Layered source is consulted for each of them. config:"-" skips
+// a nested field. Unexported nested fields are only set, with
+// AppConfig.InjectUnexported, when they carry a config tag.
+func (A *App) setByConfig(c ConfigSource, field reflect.Value, key string) {
+	t := field.Type()
+
+	if resolveTypePtr(t).Kind() == reflect.Struct && !A.isLiteralType(t) {
+		target := field
+		if t.Kind() == reflect.Ptr {
+			target = reflect.New(t.Elem()).Elem()
+		}
+		st := target.Type()
+		for fn := 0; fn < st.NumField(); fn++ {
+			sf := st.Field(fn)
+			tag, tagged := sf.Tag.Lookup("config")
+			if !sf.IsExported() && !tagged {
+				continue
+			}
+			fv, settable := A.settableField(target.Field(fn))
+			if !settable {
+				continue
+			}
+			name := sf.Name
+			if tagged && tag == "-" {
+				continue
+			} else if tagged && tag != "" {
+				name = tag
+			}
+			A.setByConfig(c, fv, key+"."+name)
+		}
+		if t.Kind() == reflect.Ptr {
+			field.Set(target.Addr())
+		}
+		return
+	}
+
+	v, ok := c.Lookup(key)
+	if !ok {
+		panic(fmt.Sprintf("config key %s not found", key))
+	}
+	A.setByConfigValue(field, v, key)
+}
+
+// setByConfigValue converts the config value v into field. Scalars, such as
+// values from ConfigEnv, are parsed like tag literals, so lists and maps may
+// also be given as "a,b" and "k=v,k2=v2" strings.
+func (A *App) setByConfigValue(field reflect.Value, v interface{}, key string) {
+	t := field.Type()
+	s, scalar := configString(v)
+
+	switch {
+	case v == nil:
+		field.Set(reflect.Zero(t))
+	case t.Kind() == reflect.Interface && reflect.TypeOf(v).AssignableTo(t):
+		field.Set(reflect.ValueOf(v))
+	case scalar && A.isLiteralType(t):
+		A.setByTagValue(field, s)
+	case t.Kind() != reflect.Slice && t.Kind() != reflect.Map && A.isLiteralType(t):
+		panic(fmt.Sprintf("config key %s holds %T, can not convert to %s", key, v, t))
+	case t.Kind() == reflect.Slice:
+		items, ok := v.([]interface{})
+		if !ok {
+			panic(fmt.Sprintf("config key %s holds %T, expected a list for %s", key, v, t))
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			A.setByConfigValue(slice.Index(i), item, fmt.Sprintf("%s.%d", key, i))
+		}
+		field.Set(slice)
+	case t.Kind() == reflect.Map:
+		m, ok := asConfigTree(v)
+		if !ok {
+			panic(fmt.Sprintf("config key %s holds %T, expected an object for %s", key, v, t))
+		}
+		result := reflect.MakeMapWithSize(t, len(m))
+		for k, item := range m {
+			mk := reflect.New(t.Key()).Elem()
+			A.setByConfigValue(mk, k, key)
+			mv := reflect.New(t.Elem()).Elem()
+			A.setByConfigValue(mv, item, key+"."+k)
+			result.SetMapIndex(mk, mv)
+		}
+		field.Set(result)
+	default:
+		panic(fmt.Sprintf("config key %s can not be converted to %s", key, t))
+	}
+}
+
+// configString renders a scalar config value in the form setByTagValue parses.
+func configString(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case bool:
+		return strconv.FormatBool(x), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(x), true
+	}
+	return "", false
+}
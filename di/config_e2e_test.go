@@ -0,0 +1,211 @@
+package di
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const configTestJSON = `{
+	"app": {"name": "shop", "debug": true},
+	"database": {
+		"host": "db.internal",
+		"pool": {"max": 20, "idle": 5},
+		"replicas": ["r1", "r2"]
+	},
+	"limits": {"upload": 1048576, "download": 4194304},
+	"ports": [8080, 8081]
+}`
+
+type ConfigTagPool struct {
+	Max  int `config:"max"`
+	Idle int `config:"idle"`
+}
+
+type ConfigTagDatabase struct {
+	Host     string         `config:"host"`
+	Pool     ConfigTagPool  `config:"pool"`
+	Replicas []string       `config:"replicas"`
+	Ignored  string         `config:"-"`
+	PoolPtr  *ConfigTagPool `config:"pool"`
+}
+
+type ConfigTagService struct {
+	Name     string            `config:"app.name"`
+	Debug    bool              `config:"app.debug"`
+	PoolMax  int               `config:"database.pool.max"`
+	Replicas []string          `config:"database.replicas"`
+	Ports    []uint16          `config:"ports"`
+	Limits   map[string]int64  `config:"limits"`
+	Database ConfigTagDatabase `config:"database"`
+	Source   ConfigSource      `inject:""`
+}
+
+type ConfigTagMissingKey struct {
+	Missing string `config:"does.not.exist"`
+}
+
+type ConfigTagWithNew struct {
+	Name  string `config:"app.name"`
+	Other string
+}
+
+func (c ConfigTagWithNew) New() *ConfigTagWithNew {
+	return &ConfigTagWithNew{Name: "from-new", Other: "kept"}
+}
+
+func writeTestConfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(configTestJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfig_JSONFileInjection(t *testing.T) {
+	file, err := JSONConfig(writeTestConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.BindConfig(file)
+
+	svc := c.Make(&ConfigTagService{}).(*ConfigTagService)
+
+	if svc.Name != "shop" || !svc.Debug {
+		t.Errorf("Expected shop/true, got %s/%v", svc.Name, svc.Debug)
+	}
+	if svc.PoolMax != 20 {
+		t.Errorf("Expected pool max 20, got %d", svc.PoolMax)
+	}
+	if len(svc.Replicas) != 2 || svc.Replicas[1] != "r2" {
+		t.Errorf("Expected replicas [r1 r2], got %v", svc.Replicas)
+	}
+	if len(svc.Ports) != 2 || svc.Ports[0] != 8080 {
+		t.Errorf("Expected ports [8080 8081], got %v", svc.Ports)
+	}
+	if svc.Limits["upload"] != 1048576 || svc.Limits["download"] != 4194304 {
+		t.Errorf("Expected limits map, got %v", svc.Limits)
+	}
+	if svc.Database.Host != "db.internal" || svc.Database.Pool.Idle != 5 || svc.Database.PoolPtr.Max != 20 {
+		t.Errorf("Expected nested struct to be filled, got %+v", svc.Database)
+	}
+	if svc.Source == nil {
+		t.Error("ConfigSource should be injectable once bound")
+	}
+}
+
+func TestConfig_JSONFileErrors(t *testing.T) {
+	if _, err := JSONConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(path, []byte("{not json"), 0o600)
+	if _, err := JSONConfig(path); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestConfig_LayeredSources(t *testing.T) {
+	file, err := JSONConfig(writeTestConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := ConfigMap{
+		"app":    map[string]interface{}{"name": "default", "debug": false},
+		"limits": map[string]interface{}{"stream": 99},
+	}
+	env := ConfigEnv{Prefix: "SHOP", Env: EnvMap{"SHOP_DATABASE_POOL_MAX": "50", "SHOP_APP_NAME": "from-env"}}
+
+	c := New()
+	c.BindConfig(defaults, file, env)
+
+	svc := c.Make(&ConfigTagService{}).(*ConfigTagService)
+
+	if svc.Name != "from-env" {
+		t.Errorf("Env should override file and defaults, got %s", svc.Name)
+	}
+	if !svc.Debug {
+		t.Error("File should override defaults")
+	}
+	if svc.PoolMax != 50 || svc.Database.Pool.Max != 50 {
+		t.Errorf("Env should override nested keys too, got %d and %d", svc.PoolMax, svc.Database.Pool.Max)
+	}
+	if svc.Limits["stream"] != 99 || svc.Limits["upload"] != 1048576 {
+		t.Errorf("Maps should merge across layers, got %v", svc.Limits)
+	}
+}
+
+func TestConfig_MissingKeyPanics(t *testing.T) {
+	c := New()
+	c.BindConfig(ConfigMap{})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for missing config key")
+		}
+	}()
+
+	c.Make(&ConfigTagMissingKey{})
+}
+
+func TestConfig_NoConfigBoundPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when no configuration is bound")
+		}
+	}()
+
+	New().Make(&ConfigTagMissingKey{})
+}
+
+func TestConfig_UnconvertibleValuePanics(t *testing.T) {
+	c := New()
+	c.BindConfig(ConfigMap{"app": map[string]interface{}{"name": "shop", "debug": "maybe"}})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for a value that can not be parsed")
+		}
+	}()
+
+	c.Make(&struct {
+		Debug bool `config:"app.debug"`
+	}{})
+}
+
+func TestConfig_MakeWithAndNew(t *testing.T) {
+	c := New()
+	c.BindConfig(ConfigMap{"app": map[string]interface{}{"name": "shop"}})
+
+	withNew := c.Make(&ConfigTagWithNew{}).(*ConfigTagWithNew)
+	if withNew.Name != "shop" || withNew.Other != "kept" {
+		t.Errorf("Config tags should apply after New(), got %+v", withNew)
+	}
+
+	overridden := c.MakeWith(&ConfigTagWithNew{}, map[string]interface{}{"Name": "override"}).(*ConfigTagWithNew)
+	if overridden.Name != "override" {
+		t.Errorf("MakeWith should take precedence over config, got %s", overridden.Name)
+	}
+}
+
+func TestConfig_ScopeInheritsConfig(t *testing.T) {
+	c := New()
+	c.BindConfig(ConfigMap{"app": map[string]interface{}{"name": "parent"}})
+
+	s := c.Scope()
+	if s.Make(&ConfigTagWithNew{}).(*ConfigTagWithNew).Name != "parent" {
+		t.Error("Scope should use its parent's configuration")
+	}
+
+	s.BindConfig(ConfigMap{"app": map[string]interface{}{"name": "scope"}})
+	if s.Make(&ConfigTagWithNew{}).(*ConfigTagWithNew).Name != "scope" {
+		t.Error("Scope configuration should replace the parent's")
+	}
+	if c.Make(&ConfigTagWithNew{}).(*ConfigTagWithNew).Name != "parent" {
+		t.Error("Scope configuration should not affect the parent")
+	}
+}
@@ -0,0 +1,53 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ContextBindFunc is a factory function that receives the context of the
+// resolution it runs in alongside the container. Returning an error aborts
+// the resolution. Accepted anywhere a BindFunc is.
+type ContextBindFunc func(context.Context, *App) (interface{}, error)
+
+var (
+	contextType         = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType           = reflect.TypeOf((*error)(nil)).Elem()
+	contextBindFuncType = reflect.TypeOf(ContextBindFunc(nil))
+	contextKey          = typeFullName(reflect.TypeOf((*context.Context)(nil)))
+)
+
+// MakeContext resolves type a like Make, passing ctx to any ContextBindFunc
+// and to fields or New() parameters of type context.Context. The resolution
+// panics with an error wrapping ctx.Err() once ctx is cancelled or its
+// deadline passes.
+func (A *App) MakeContext(ctx context.Context, a interface{}) interface{} {
+	if ctx == nil {
+		panic("MakeContext() requires a non-nil context")
+	}
+	r, exit := A.enterContext(ctx)
+	defer exit()
+	return r.makeInternal(a)
+}
+
+// Context returns the context of the resolution A is running in, or
+// context.Background() outside of one.
+func (A *App) Context() context.Context {
+	if A.res == nil || A.res.ctx == nil {
+		return context.Background()
+	}
+	return A.res.ctx
+}
+
+// checkContext aborts the resolution of key if its context is done.
+func (A *App) checkContext(key string) {
+	if err := A.Context().Err(); err != nil {
+		panic(fmt.Errorf("resolving %s aborted: %w", key, err))
+	}
+}
+
+// isBindFuncType reports whether t can be run as a BindFunc.
+func isBindFuncType(t reflect.Type) bool {
+	return t.ConvertibleTo(bindFuncType) || t.ConvertibleTo(interfaceBindFuncType) || t.ConvertibleTo(contextBindFuncType)
+}
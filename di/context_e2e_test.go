@@ -0,0 +1,159 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type ctxTestKey struct{}
+
+type ContextTraced struct {
+	Ctx  context.Context `inject:""`
+	Name string          `inject:"traced"`
+}
+
+type ContextTracedWithNew struct {
+	TraceID string
+}
+
+func (c ContextTracedWithNew) New(ctx context.Context) *ContextTracedWithNew {
+	id, _ := ctx.Value(ctxTestKey{}).(string)
+	return &ContextTracedWithNew{TraceID: id}
+}
+
+func TestMakeContext_ContextBindFuncReceivesContext(t *testing.T) {
+	c := New()
+
+	c.Bind((*UserService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		name, _ := ctx.Value(ctxTestKey{}).(string)
+		return &MockUserService{Name: name}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxTestKey{}, "trace-1")
+	svc := c.MakeContext(ctx, (*UserService)(nil)).(UserService)
+
+	if svc.GetUserName() != "trace-1" {
+		t.Errorf("Expected trace-1, got %s", svc.GetUserName())
+	}
+}
+
+func TestMakeContext_PlainMakeUsesBackground(t *testing.T) {
+	c := New()
+
+	c.Bind((*UserService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		if ctx != context.Background() {
+			t.Error("Make should run ContextBindFuncs with context.Background()")
+		}
+		return &MockUserService{}, nil
+	})
+
+	c.Make((*UserService)(nil))
+}
+
+func TestMakeContext_ContextBindFuncError(t *testing.T) {
+	c := New()
+
+	failure := errors.New("backend unavailable")
+	c.Bind((*UserService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		return nil, failure
+	})
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, failure) {
+			t.Errorf("Expected panic with the factory's error, got %v", r)
+		}
+	}()
+
+	c.Make((*UserService)(nil))
+}
+
+func TestMakeContext_ContextInjectedIntoFieldsAndNew(t *testing.T) {
+	c := New()
+
+	ctx := context.WithValue(context.Background(), ctxTestKey{}, "trace-2")
+
+	traced := c.MakeContext(ctx, &ContextTraced{}).(*ContextTraced)
+	if traced.Ctx != ctx {
+		t.Error("context.Context field should receive the resolution's context")
+	}
+
+	withNew := c.MakeContext(ctx, &ContextTracedWithNew{}).(*ContextTracedWithNew)
+	if withNew.TraceID != "trace-2" {
+		t.Errorf("Expected New() to receive the context, got trace ID %q", withNew.TraceID)
+	}
+}
+
+func TestMakeContext_NestedMakeInheritsContext(t *testing.T) {
+	c := New()
+
+	c.Bind((*EmailService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		if ctx.Value(ctxTestKey{}) != "outer" {
+			t.Error("Nested Make should inherit the outer resolution's context")
+		}
+		return &MockEmailService{}, nil
+	})
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		a.Make((*EmailService)(nil))
+		return &MockUserService{}
+	})
+
+	c.MakeContext(context.WithValue(context.Background(), ctxTestKey{}, "outer"), (*UserService)(nil))
+}
+
+func TestMakeContext_CancelledContextAborts(t *testing.T) {
+	c := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected panic wrapping context.Canceled, got %v", r)
+		}
+	}()
+
+	c.MakeContext(ctx, &ContextTraced{})
+}
+
+func TestMakeContext_DeadlineAbortsLongResolution(t *testing.T) {
+	c := New()
+
+	c.Bind((*EmailService)(nil), &MockEmailService{})
+	c.Bind((*UserService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		<-ctx.Done()
+		return &MockUserService{}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected panic wrapping context.DeadlineExceeded, got %v", r)
+		}
+	}()
+
+	// UserController needs UserService then EmailService; the deadline passes
+	// while the first is built, so the second is never resolved
+	c.MakeContext(ctx, &UserController{})
+}
+
+func TestMakeContext_SingletonWithContextBindFunc(t *testing.T) {
+	c := New()
+
+	c.Singleton((*UserService)(nil), func(ctx context.Context, a *App) (interface{}, error) {
+		return &MockUserService{Name: "shared"}, nil
+	})
+
+	if c.Make((*UserService)(nil)) != c.Make((*UserService)(nil)) {
+		t.Error("Singleton bound to a ContextBindFunc should return the same instance")
+	}
+}
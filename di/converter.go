@@ -0,0 +1,65 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Converter parses a tag literal, env variable or config value into a value
+// of the type it is registered for.
+type Converter func(string) (interface{}, error)
+
+// RegisterConverter teaches the container to parse values of type t from
+// inject literals, env tags and config values. Registered converters take
+// precedence over the built-in parsing, also for elements of slices and maps
+// of t. Pass nil to remove a converter. Scopes use their parent's converters
+// unless they register their own for a type.
+func (A *App) RegisterConverter(t reflect.Type, c func(string) (interface{}, error)) AppInterface {
+	A.checkFrozen("RegisterConverter")
+	if t == nil {
+		panic("RegisterConverter() requires a non-nil type")
+	}
+
+	defer A.writeLock("RegisterConverter")()
+
+	if c == nil {
+		delete(A.converters, t)
+		return A
+	}
+	if A.converters == nil {
+		A.converters = make(map[reflect.Type]Converter)
+	}
+	A.converters[t] = c
+
+	return A
+}
+
+// converter returns the Converter registered for t, falling back to the
+// parent container for scopes.
+func (A *App) converter(t reflect.Type) Converter {
+	unlock := A.readLock()
+	c := A.converters[t]
+	unlock()
+	if c == nil && A.parent != nil {
+		return A.parent.converter(t)
+	}
+	return c
+}
+
+// setByConverter runs c on v and stores the result in f.
+func (A *App) setByConverter(f reflect.Value, c Converter, v string) {
+	r, err := c(v)
+	if err != nil {
+		panic(err)
+	}
+
+	rv := reflect.ValueOf(r)
+	switch {
+	case r == nil:
+		f.Set(reflect.Zero(f.Type()))
+	case rv.Type().AssignableTo(f.Type()):
+		f.Set(rv)
+	default:
+		panic(fmt.Sprintf("converter for %s returned %s", f.Type(), rv.Type()))
+	}
+}
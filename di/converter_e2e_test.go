@@ -0,0 +1,134 @@
+package di
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ConverterMoney struct {
+	Currency string
+	Cents    int64
+}
+
+func parseMoney(s string) (interface{}, error) {
+	currency, amount, ok := strings.Cut(s, " ")
+	if !ok {
+		return nil, errors.New("expected \"CUR amount\"")
+	}
+	var cents int64
+	for _, r := range strings.ReplaceAll(amount, ".", "") {
+		cents = cents*10 + int64(r-'0')
+	}
+	return ConverterMoney{Currency: currency, Cents: cents}, nil
+}
+
+type ConverterUpper string
+
+type ConverterPriced struct {
+	Price  ConverterMoney            `inject:"EUR 12.50"`
+	Tiers  []ConverterMoney          `inject:"EUR 1.00,EUR 2.00"`
+	ByName map[string]ConverterMoney `inject:"basic=USD 5.00"`
+	Label  ConverterUpper            `inject:"sale"`
+	Dual   ConverterMoney            `di:"" inject:"GBP 1.00"`
+	Env    ConverterMoney            `env:"PRICE"`
+	Config ConverterMoney            `config:"shop.price"`
+}
+
+type ConverterPricedWithNew struct {
+	Price ConverterMoney `inject:"EUR 3.00"`
+}
+
+func (c ConverterPricedWithNew) New() *ConverterPricedWithNew {
+	return &ConverterPricedWithNew{}
+}
+
+func newConverterApp() *App {
+	c := New(AppConfig{Env: EnvMap{"PRICE": "CHF 7.00"}})
+	c.BindConfig(ConfigMap{"shop": map[string]interface{}{"price": "JPY 900"}})
+	c.RegisterConverter(reflect.TypeOf(ConverterMoney{}), parseMoney)
+	c.RegisterConverter(reflect.TypeOf(ConverterUpper("")), func(s string) (interface{}, error) {
+		return ConverterUpper(strings.ToUpper(s)), nil
+	})
+	return c
+}
+
+func TestConverter_AppliesToAllSources(t *testing.T) {
+	c := newConverterApp()
+
+	p := c.Make(&ConverterPriced{}).(*ConverterPriced)
+
+	if p.Price != (ConverterMoney{"EUR", 1250}) {
+		t.Errorf("Expected EUR 1250, got %+v", p.Price)
+	}
+	if len(p.Tiers) != 2 || p.Tiers[1].Cents != 200 {
+		t.Errorf("Expected converter for slice elements, got %+v", p.Tiers)
+	}
+	if p.ByName["basic"].Currency != "USD" {
+		t.Errorf("Expected converter for map values, got %+v", p.ByName)
+	}
+	if p.Label != "SALE" {
+		t.Errorf("Converter should take precedence over kind parsing, got %s", p.Label)
+	}
+	if p.Dual.Currency != "GBP" {
+		t.Errorf("Dual di+inject tag should use the converter, got %+v", p.Dual)
+	}
+	if p.Env.Currency != "CHF" || p.Config.Cents != 900 {
+		t.Errorf("Expected env and config through the converter, got %+v %+v", p.Env, p.Config)
+	}
+}
+
+func TestConverter_AppliesAfterNew(t *testing.T) {
+	p := newConverterApp().Make(&ConverterPricedWithNew{}).(*ConverterPricedWithNew)
+
+	if p.Price.Cents != 300 {
+		t.Errorf("Expected converter after New(), got %+v", p.Price)
+	}
+}
+
+func TestConverter_ErrorPanics(t *testing.T) {
+	c := newConverterApp()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when the converter returns an error")
+		}
+	}()
+
+	c.Make(&struct {
+		Price ConverterMoney `inject:"broken"`
+	}{})
+}
+
+func TestConverter_WrongResultTypePanics(t *testing.T) {
+	c := New()
+	c.RegisterConverter(reflect.TypeOf(ConverterMoney{}), func(s string) (interface{}, error) {
+		return 42, nil
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when the converter returns an incompatible type")
+		}
+	}()
+
+	c.Make(&ConverterPricedWithNew{})
+}
+
+func TestConverter_RemoveAndScope(t *testing.T) {
+	c := newConverterApp()
+
+	if c.Scope().Make(&ConverterPricedWithNew{}).(*ConverterPricedWithNew).Price.Cents != 300 {
+		t.Error("Scope should use its parent's converters")
+	}
+
+	c.RegisterConverter(reflect.TypeOf(ConverterUpper("")), nil)
+	if c.Make(&struct {
+		Label ConverterUpper `inject:"sale"`
+	}{}).(*struct {
+		Label ConverterUpper `inject:"sale"`
+	}).Label != "sale" {
+		t.Error("Removed converter should fall back to kind parsing")
+	}
+}
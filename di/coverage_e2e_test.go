@@ -1,6 +1,8 @@
 package di
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -470,6 +472,36 @@ func TestWhen_GiveSingleton(t *testing.T) {
 	}
 }
 
+func TestWhen_GiveSingletonFuncBuiltOnce(t *testing.T) {
+	c := New()
+
+	var builds int32
+	c.When(&WhenSingletonConsumer{}).Needs((*WhenSingletonService)(nil)).Give(func(a *App) interface{} {
+		atomic.AddInt32(&builds, 1)
+		return &WhenSingletonService{Count: 7}
+	}).Singleton()
+
+	var wg sync.WaitGroup
+	consumers := make([]*WhenSingletonConsumer, 8)
+	for i := range consumers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			consumers[i] = c.Make(&WhenSingletonConsumer{}).(*WhenSingletonConsumer)
+		}(i)
+	}
+	wg.Wait()
+
+	if builds != 1 {
+		t.Errorf("Expected singleton BindFunc to run once, ran %d times", builds)
+	}
+	for _, consumer := range consumers {
+		if consumer.Service != consumers[0].Service || consumer.Service.Count != 7 {
+			t.Fatal("All consumers should share the singleton built by the BindFunc")
+		}
+	}
+}
+
 // --- Deep nesting with When override at middle level ---
 
 type DeepWhenDB interface {
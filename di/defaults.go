@@ -0,0 +1,25 @@
+package di
+
+// BindDefault registers b as the fallback implementation for a, accepting
+// the same combinations as Bind. It is only used while no Bind or Singleton
+// exists for a on the container or its parents, so a library can ship
+// defaults that applications override regardless of registration order.
+// Pass nil as b to remove a default.
+func (A *App) BindDefault(a interface{}, b interface{}) AppInterface {
+	A.checkFrozen("BindDefault")
+	r, exit := A.enter()
+	defer exit()
+
+	if b == nil {
+		r.deleteEntry("BindDefault", defaultsOf, a)
+		return A
+	}
+
+	label, o := r.newBinding(a, b)
+
+	unlock := r.writeLock("BindDefault")
+	r.defaults[label] = o
+	unlock()
+
+	return A
+}
@@ -0,0 +1,104 @@
+package di
+
+import (
+	"testing"
+)
+
+type DefaultLogger interface {
+	Name() string
+}
+
+type DefaultNoopLogger struct{}
+
+func (d DefaultNoopLogger) Name() string { return "noop" }
+
+type DefaultAppLogger struct{}
+
+func (d *DefaultAppLogger) Name() string { return "app" }
+
+type DefaultLibraryClient struct {
+	Logger DefaultLogger `inject:""`
+}
+
+type DefaultOptionalClient struct {
+	Logger DefaultLogger `inject:",optional"`
+}
+
+func TestBindDefault_UsedWhenUnbound(t *testing.T) {
+	c := New()
+	c.BindDefault((*DefaultLogger)(nil), DefaultNoopLogger{})
+
+	client := c.Make(&DefaultLibraryClient{}).(*DefaultLibraryClient)
+	if client.Logger.Name() != "noop" {
+		t.Errorf("Expected default logger, got %s", client.Logger.Name())
+	}
+}
+
+func TestBindDefault_ExplicitBindWinsRegardlessOfOrder(t *testing.T) {
+	before := New()
+	before.Bind((*DefaultLogger)(nil), &DefaultAppLogger{})
+	before.BindDefault((*DefaultLogger)(nil), DefaultNoopLogger{})
+
+	after := New()
+	after.BindDefault((*DefaultLogger)(nil), DefaultNoopLogger{})
+	after.Bind((*DefaultLogger)(nil), &DefaultAppLogger{})
+
+	for _, c := range []*App{before, after} {
+		if c.Make((*DefaultLogger)(nil)).(DefaultLogger).Name() != "app" {
+			t.Error("Explicit binding should win over a default")
+		}
+	}
+}
+
+func TestBindDefault_RemovingBindingRestoresDefault(t *testing.T) {
+	c := New()
+	c.BindDefault((*DefaultLogger)(nil), DefaultNoopLogger{})
+	c.Bind((*DefaultLogger)(nil), &DefaultAppLogger{})
+	c.Bind((*DefaultLogger)(nil), nil)
+
+	if c.Make((*DefaultLogger)(nil)).(DefaultLogger).Name() != "noop" {
+		t.Error("Removing the explicit binding should fall back to the default")
+	}
+
+	c.BindDefault((*DefaultLogger)(nil), nil)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic once the default is removed too")
+		}
+	}()
+	c.Make((*DefaultLogger)(nil))
+}
+
+func TestBindDefault_ParentBindingWinsOverScopeDefault(t *testing.T) {
+	c := New()
+	c.Bind((*DefaultLogger)(nil), &DefaultAppLogger{})
+
+	s := c.Scope()
+	s.BindDefault((*DefaultLogger)(nil), DefaultNoopLogger{})
+
+	if s.Make((*DefaultLogger)(nil)).(DefaultLogger).Name() != "app" {
+		t.Error("An explicit binding on the parent should win over a default on the scope")
+	}
+}
+
+func TestBindDefault_SatisfiesOptional(t *testing.T) {
+	c := New()
+	c.BindDefault((*DefaultLogger)(nil), func(a *App) interface{} {
+		return DefaultNoopLogger{}
+	})
+
+	if c.Make(&DefaultOptionalClient{}).(*DefaultOptionalClient).Logger == nil {
+		t.Error("A default should satisfy an optional dependency")
+	}
+}
+
+func TestBindDefault_InvalidCombinationPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for an incompatible default")
+		}
+	}()
+
+	New().BindDefault((*DefaultLogger)(nil), &struct{}{})
+}
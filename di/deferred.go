@@ -0,0 +1,142 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// DeferredServiceProvider is a ServiceProvider loaded on first use. Instead
+// of being registered by Boot, its Register and then Boot run the first time
+// a key listed by Provides is resolved without a binding. Keys are given as
+// to Bind: (*Interface)(nil), &Struct{} or a string name. Register must bind
+// every key it provides.
+type DeferredServiceProvider interface {
+	ServiceProvider
+	Provides() []interface{}
+}
+
+// deferredProvider is a DeferredServiceProvider recorded under its keys.
+// failure holds what a failed load panicked with; it is raised again on
+// every later resolve of the keys.
+type deferredProvider struct {
+	p       ServiceProvider
+	keys    []string
+	mu      sync.Mutex
+	loaded  atomic.Bool
+	failure interface{}
+}
+
+// deferProvider records p under the keys it provides.
+func (A *App) deferProvider(p DeferredServiceProvider) {
+	provides := p.Provides()
+	keys := make([]string, 0, len(provides))
+	for _, k := range provides {
+		switch t := reflect.TypeOf(k); {
+		case t == nil:
+			panic(fmt.Sprintf("%T.Provides() returned nil", p))
+		case t.Kind() == reflect.String:
+			keys = append(keys, k.(string))
+		default:
+			keys = append(keys, A.typeFullName(t))
+		}
+	}
+
+	d := &deferredProvider{p: p, keys: keys}
+	unlock := A.writeLock("RegisterProvider")
+	if A.deferred == nil {
+		A.deferred = make(map[string]*deferredProvider)
+	}
+	for _, k := range keys {
+		A.deferred[k] = d
+	}
+	A.pending.Add(1)
+	unlock()
+}
+
+// findDeferred returns the deferred provider recorded for key and the
+// container it was registered on, falling back to parents for scopes.
+func (A *App) findDeferred(key string) (*App, *deferredProvider) {
+	for c := A; c != nil; c = c.parent {
+		unlock := c.readLock()
+		d := c.deferred[key]
+		unlock()
+		if d != nil {
+			return c, d
+		}
+	}
+	return nil, nil
+}
+
+// loadDeferred registers and boots the deferred provider for key, if it has
+// not been loaded yet, and reports whether key has one. It also works on a
+// frozen container. Concurrent
+// resolutions wait for the load; the provider's own Register and Boot, which
+// run in this resolution, pass straight through. If Register panics or Boot
+// fails, the provider's keys are unbound again and the failure is raised by
+// this and every later load.
+func (A *App) loadDeferred(key string) bool {
+	owner, d := A.findDeferred(key)
+	if d == nil {
+		return false
+	}
+	if d.loaded.Load() {
+		d.raise()
+		return true
+	}
+
+	// Marks the provider as loading in this resolution's resolving map,
+	// alongside the type keys
+	marker := fmt.Sprintf("deferred provider %p", d)
+	if A.res.resolving[marker] {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.loaded.Load() {
+		d.raise()
+		return true
+	}
+
+	owner.appMu.RLock()
+	prev := make([]ObjectInterface, len(d.keys))
+	for i, k := range d.keys {
+		prev[i] = owner.registry[k]
+	}
+	owner.appMu.RUnlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			owner.appMu.Lock()
+			for i, k := range d.keys {
+				setBinding(owner.registry, k, prev[i])
+			}
+			owner.appMu.Unlock()
+			d.failure = r
+		}
+		d.loaded.Store(true)
+		owner.pending.Add(-1)
+		d.raise()
+	}()
+
+	A.res.resolving[marker] = true
+	defer delete(A.res.resolving, marker)
+
+	// Bindings go to the container the provider was registered on, which
+	// may be frozen by now; the loading resolver may still write to it
+	r := &App{container: owner.container, res: A.res, loading: d}
+	d.p.Register(r)
+	if err := d.p.Boot(r); err != nil {
+		panic(fmt.Errorf("booting %T: %w", d.p, err))
+	}
+	return true
+}
+
+// raise panics with the failure of a provider whose load failed.
+func (d *deferredProvider) raise() {
+	if d.failure != nil {
+		panic(d.failure)
+	}
+}
@@ -0,0 +1,193 @@
+package di
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type DeferredCache interface {
+	Get() string
+}
+
+type DeferredMemCache struct{}
+
+func (d *DeferredMemCache) Get() string { return "mem" }
+
+type DeferredQueue struct {
+	Name string
+}
+
+type DeferredCacheProvider struct {
+	registered atomic.Int32
+	booted     atomic.Int32
+	bootErr    error
+}
+
+func (p *DeferredCacheProvider) Provides() []interface{} {
+	return []interface{}{(*DeferredCache)(nil), &DeferredQueue{}, "cache.name"}
+}
+
+func (p *DeferredCacheProvider) Register(a *App) {
+	p.registered.Add(1)
+	a.Singleton((*DeferredCache)(nil), &DeferredMemCache{})
+	a.Bind(&DeferredQueue{}, func(a *App) interface{} {
+		return &DeferredQueue{Name: "jobs"}
+	})
+	a.Bind("cache.name", &DeferredMemCache{})
+}
+
+func (p *DeferredCacheProvider) Boot(a *App) error {
+	p.booted.Add(1)
+	// Resolving a provided key while loading must not reload or deadlock
+	a.Make((*DeferredCache)(nil))
+	return p.bootErr
+}
+
+type DeferredCacheConsumer struct {
+	Cache DeferredCache `inject:",optional"`
+}
+
+func TestDeferredProvider_LoadedOnFirstMiss(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.RegisterProvider(p)
+
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+	if p.registered.Load() != 0 {
+		t.Fatal("Deferred provider should not be registered by Boot")
+	}
+
+	if c.Make((*DeferredCache)(nil)).(DeferredCache).Get() != "mem" {
+		t.Error("Expected deferred binding to resolve")
+	}
+	if c.Make(&DeferredQueue{}).(*DeferredQueue).Name != "jobs" {
+		t.Error("Expected the other provided key to resolve")
+	}
+	if p.registered.Load() != 1 || p.booted.Load() != 1 {
+		t.Errorf("Expected one Register and one Boot, got %d and %d", p.registered.Load(), p.booted.Load())
+	}
+}
+
+func TestDeferredProvider_NotLoadedForOtherKeys(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.RegisterProvider(p)
+
+	c.Make(&DeferredMemCache{})
+	if p.registered.Load() != 0 {
+		t.Error("Resolving a key the provider does not provide should not load it")
+	}
+}
+
+func TestDeferredProvider_ExplicitBindingSkipsLoad(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.RegisterProvider(p)
+	c.Bind(&DeferredQueue{}, func(a *App) interface{} {
+		return &DeferredQueue{Name: "explicit"}
+	})
+
+	if c.Make(&DeferredQueue{}).(*DeferredQueue).Name != "explicit" || p.registered.Load() != 0 {
+		t.Error("Expected an existing binding to be used without loading the provider")
+	}
+}
+
+func TestDeferredProvider_ConcurrentFirstUse(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.RegisterProvider(p)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Make((*DeferredCache)(nil))
+		}()
+	}
+	wg.Wait()
+
+	if p.registered.Load() != 1 {
+		t.Errorf("Expected exactly one Register, got %d", p.registered.Load())
+	}
+}
+
+func TestDeferredProvider_OptionalAndScope(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.RegisterProvider(p)
+
+	s := c.Scope()
+	consumer := s.Make(&DeferredCacheConsumer{}).(*DeferredCacheConsumer)
+	if consumer.Cache == nil {
+		t.Error("Expected an optional dependency to load the deferred provider")
+	}
+	if s.Make("cache.name").(*DeferredMemCache).Get() != "mem" {
+		t.Error("Expected string key to resolve")
+	}
+
+	if err := s.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Make((*DeferredCache)(nil)) == nil {
+		t.Error("Expected bindings to be made on the container the provider was registered on")
+	}
+}
+
+func TestDeferredProvider_BootErrorPanics(t *testing.T) {
+	c := New()
+	bootErr := errors.New("no cache")
+	c.RegisterProvider(&DeferredCacheProvider{bootErr: bootErr})
+
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, bootErr) {
+					t.Errorf("Expected panic wrapping the Boot error on resolve %d, got %v", i+1, err)
+				}
+			}()
+			c.Make(&DeferredQueue{})
+		}()
+	}
+}
+
+func TestDeferredProvider_BootErrorKeepsFailing(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{bootErr: errors.New("no cache")}
+	c.RegisterProvider(p)
+
+	for _, key := range []interface{}{&DeferredQueue{}, (*DeferredCache)(nil), "cache.name"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected resolving %T after a failed Boot to panic", key)
+				}
+			}()
+			c.Make(key)
+		}()
+	}
+	if p.registered.Load() != 1 || p.booted.Load() != 1 {
+		t.Errorf("Expected a failed provider to be loaded once, got %d registers and %d boots", p.registered.Load(), p.booted.Load())
+	}
+}
+
+func TestDeferredProvider_LoadedBeforeDefault(t *testing.T) {
+	c := New()
+	p := &DeferredCacheProvider{}
+	c.BindDefault(&DeferredQueue{}, func(a *App) interface{} {
+		return &DeferredQueue{Name: "default"}
+	})
+	c.RegisterProvider(p)
+
+	if q := c.Make(&DeferredQueue{}).(*DeferredQueue); q.Name != "jobs" {
+		t.Errorf("Expected the deferred provider's binding over the default, got %s", q.Name)
+	}
+	if p.registered.Load() != 1 {
+		t.Error("Expected the default not to stop the provider loading")
+	}
+}
@@ -0,0 +1,81 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Register adds concrete types to the catalogue searched when the container
+// was created with AppConfig.AutoBind: an interface with no binding then
+// resolves to the single registered type implementing it. Types are given as
+// values, e.g. Register(&SMTPMailer{}, FileStore{}); whether a pointer or a
+// struct is registered decides which method set is checked and what Make
+// returns. Scopes search their own catalogue and then their parent's.
+func (A *App) Register(types ...interface{}) AppInterface {
+	A.checkFrozen("Register")
+	ts := make([]reflect.Type, 0, len(types))
+	for _, x := range types {
+		t := reflect.TypeOf(x)
+		if t == nil || resolveTypePtr(t).Kind() != reflect.Struct {
+			panic(fmt.Sprintf("Register() requires structs or pointers to structs, got %T", x))
+		}
+		ts = append(ts, t)
+	}
+
+	unlock := A.writeLock("Register")
+	for _, t := range ts {
+		if !containsType(A.catalogue, t) {
+			A.catalogue = append(A.catalogue, t)
+		}
+	}
+	unlock()
+
+	return A
+}
+
+// discover returns a value of the one catalogued type implementing iface,
+// ready to be passed to Make. It panics when several types qualify.
+func (A *App) discover(iface reflect.Type) (interface{}, bool) {
+	if !A.autoBind {
+		return nil, false
+	}
+
+	var matches []reflect.Type
+	for c := A; c != nil; c = c.parent {
+		unlock := c.readLock()
+		catalogue := c.catalogue
+		unlock()
+		for _, t := range catalogue {
+			if A.typeChecker.IsTypeCompatible(iface, t, false) && !containsType(matches, t) {
+				matches = append(matches, t)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false
+	case 1:
+		t := matches[0]
+		if t.Kind() == reflect.Ptr {
+			return reflect.New(t.Elem()).Interface(), true
+		}
+		return reflect.New(t).Elem().Interface(), true
+	}
+
+	names := make([]string, len(matches))
+	for i, t := range matches {
+		names[i] = t.String()
+	}
+	panic(fmt.Sprintf("ambiguous auto-binding for %s: implemented by %s, bind one explicitly", iface, strings.Join(names, ", ")))
+}
+
+func containsType(ts []reflect.Type, t reflect.Type) bool {
+	for _, x := range ts {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package di
+
+import (
+	"strings"
+	"testing"
+)
+
+type DiscoveryMailer interface {
+	Send() string
+}
+
+type DiscoveryStore interface {
+	Save() string
+}
+
+type DiscoverySMTP struct {
+	Host string `inject:"localhost"`
+}
+
+func (d *DiscoverySMTP) Send() string { return "smtp:" + d.Host }
+
+type DiscoveryFileStore struct{}
+
+func (d DiscoveryFileStore) Save() string { return "file" }
+
+type DiscoveryMemStore struct{}
+
+func (d DiscoveryMemStore) Save() string { return "mem" }
+
+type DiscoveryConsumer struct {
+	Mailer DiscoveryMailer `inject:""`
+}
+
+type DiscoveryOptionalConsumer struct {
+	Mailer DiscoveryMailer          `inject:",optional"`
+	Store  Optional[DiscoveryStore] `inject:""`
+}
+
+func TestAutoBind_ResolvesUniqueImplementation(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+	c.Register(&DiscoverySMTP{}, DiscoveryFileStore{})
+
+	m := c.Make((*DiscoveryMailer)(nil)).(DiscoveryMailer)
+	if m.Send() != "smtp:localhost" {
+		t.Errorf("Expected auto-generated DiscoverySMTP, got %s", m.Send())
+	}
+
+	consumer := c.Make(&DiscoveryConsumer{}).(*DiscoveryConsumer)
+	if consumer.Mailer == nil {
+		t.Error("Expected inject field to be auto-bound")
+	}
+}
+
+func TestAutoBind_Ambiguous(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+	c.Register(DiscoveryFileStore{}, DiscoveryMemStore{})
+
+	defer func() {
+		r := recover()
+		msg, _ := r.(string)
+		if !strings.Contains(msg, "ambiguous") || !strings.Contains(msg, "DiscoveryFileStore") || !strings.Contains(msg, "DiscoveryMemStore") {
+			t.Errorf("Expected ambiguity panic naming both types, got %v", r)
+		}
+	}()
+	c.Make((*DiscoveryStore)(nil))
+}
+
+func TestAutoBind_ExplicitBindingWins(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+	c.Register(DiscoveryFileStore{}, DiscoveryMemStore{})
+	c.Bind((*DiscoveryStore)(nil), DiscoveryMemStore{})
+
+	if c.Make((*DiscoveryStore)(nil)).(DiscoveryStore).Save() != "mem" {
+		t.Error("Expected explicit binding to take precedence over auto-binding")
+	}
+}
+
+func TestAutoBind_ImplementationBindingsApply(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+	c.Register(&DiscoverySMTP{})
+	c.Singleton(&DiscoverySMTP{Host: "mail"})
+
+	first := c.Make((*DiscoveryMailer)(nil))
+	if first != c.Make((*DiscoveryMailer)(nil)) || first.(DiscoveryMailer).Send() != "smtp:mail" {
+		t.Error("Expected the singleton bound for the implementation to be used")
+	}
+}
+
+func TestAutoBind_DisabledByDefault(t *testing.T) {
+	c := New()
+	c.Register(&DiscoverySMTP{})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic without AutoBind")
+		}
+	}()
+	c.Make((*DiscoveryMailer)(nil))
+}
+
+func TestAutoBind_ScopeSearchesParentCatalogue(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+	c.Register(DiscoveryFileStore{})
+
+	s := c.Scope()
+	if s.Make((*DiscoveryStore)(nil)).(DiscoveryStore).Save() != "file" {
+		t.Error("Expected scope to find the parent's registered type")
+	}
+
+	s.Register(DiscoveryMemStore{})
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected types registered on scope and parent to be ambiguous")
+		}
+	}()
+	s.Make((*DiscoveryStore)(nil))
+}
+
+func TestAutoBind_OptionalInjection(t *testing.T) {
+	c := New(AppConfig{AutoBind: true})
+
+	empty := c.Make(DiscoveryOptionalConsumer{}).(DiscoveryOptionalConsumer)
+	if _, ok := empty.Store.Get(); empty.Mailer != nil || ok {
+		t.Error("Expected optional dependencies to stay empty with nothing registered")
+	}
+
+	c.Register(&DiscoverySMTP{}, DiscoveryFileStore{})
+	got := c.Make(DiscoveryOptionalConsumer{}).(DiscoveryOptionalConsumer)
+	if got.Mailer == nil || got.Mailer.Send() != "smtp:localhost" {
+		t.Error("Expected the optional field to be auto-bound")
+	}
+	if s, ok := got.Store.Get(); !ok || s.Save() != "file" {
+		t.Error("Expected the Optional to be auto-bound")
+	}
+}
+
+func TestRegister_RejectsNonStruct(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic registering a non-struct type")
+		}
+	}()
+	New().Register("not-a-type")
+}
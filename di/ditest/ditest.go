@@ -0,0 +1,29 @@
+// Package ditest provides helpers for tests using a di container: bindings
+// overridden for the duration of a test, and an isolated Default().
+package ditest
+
+import (
+	"testing"
+
+	"github.com/daforester/go-di-container/di"
+)
+
+// Override binds impl for key on app until the test and its subtests end,
+// then restores the previous binding, or none. key is a type or name as for
+// Bind, or app.When(...).Needs(...) to override a contextual rule. Instances
+// are registered as singletons, so a mock given as impl is exactly what gets
+// resolved; BindFuncs run on every resolve as with Bind.
+func Override(t testing.TB, app di.AppInterface, key interface{}, impl interface{}) {
+	t.Helper()
+	t.Cleanup(app.Override(key, impl))
+}
+
+// Isolated gives the test its own default container: di.Default() and
+// named instances start empty, and the previous ones are put back once the
+// test ends. The default container is process-wide, so tests using Isolated
+// must not run in parallel with each other or with tests using Default.
+func Isolated(t testing.TB) *di.App {
+	t.Helper()
+	t.Cleanup(di.Isolate())
+	return di.Default().(*di.App)
+}
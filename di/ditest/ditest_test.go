@@ -0,0 +1,114 @@
+package ditest
+
+import (
+	"testing"
+
+	"github.com/daforester/go-di-container/di"
+)
+
+type Mailer interface {
+	Send() string
+}
+
+type SMTPMailer struct{}
+
+func (m *SMTPMailer) Send() string { return "smtp" }
+
+type MockMailer struct {
+	Sent int
+}
+
+func (m *MockMailer) Send() string {
+	m.Sent++
+	return "mock"
+}
+
+type Signup struct {
+	Mailer Mailer `inject:""`
+}
+
+type Newsletter struct {
+	Mailer Mailer `inject:""`
+}
+
+func TestOverride_RestoresPreviousBinding(t *testing.T) {
+	app := di.New()
+	app.Bind((*Mailer)(nil), &SMTPMailer{})
+	mock := &MockMailer{}
+
+	t.Run("overridden", func(t *testing.T) {
+		Override(t, app, (*Mailer)(nil), mock)
+
+		s := app.Make(&Signup{}).(*Signup)
+		s.Mailer.Send()
+		if mock.Sent != 1 {
+			t.Error("Expected the mock instance to be injected")
+		}
+	})
+
+	if app.Make((*Mailer)(nil)).(Mailer).Send() != "smtp" {
+		t.Error("Expected the original binding to be restored after the subtest")
+	}
+}
+
+func TestOverride_RemovesBindingThatDidNotExist(t *testing.T) {
+	app := di.New()
+
+	t.Run("overridden", func(t *testing.T) {
+		Override(t, app, (*Mailer)(nil), func(a *di.App) interface{} {
+			return &MockMailer{}
+		})
+		if app.Make((*Mailer)(nil)).(Mailer).Send() != "mock" {
+			t.Error("Expected the BindFunc override to resolve")
+		}
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected Mailer to be unbound again")
+		}
+	}()
+	app.Make((*Mailer)(nil))
+}
+
+func TestOverride_ContextualRule(t *testing.T) {
+	app := di.New()
+	app.Bind((*Mailer)(nil), &SMTPMailer{})
+	app.When(&Signup{}).Needs((*Mailer)(nil)).Give(&SMTPMailer{})
+
+	t.Run("overridden", func(t *testing.T) {
+		Override(t, app, app.When(&Signup{}).Needs((*Mailer)(nil)), &MockMailer{})
+
+		if app.Make(&Signup{}).(*Signup).Mailer.Send() != "mock" {
+			t.Error("Expected the contextual rule to be overridden")
+		}
+		if app.Make(&Newsletter{}).(*Newsletter).Mailer.Send() != "smtp" {
+			t.Error("Expected other requesters to be unaffected")
+		}
+	})
+
+	if app.Make(&Signup{}).(*Signup).Mailer.Send() != "smtp" {
+		t.Error("Expected the contextual rule to be restored")
+	}
+}
+
+func TestIsolated_FreshDefault(t *testing.T) {
+	outer := di.Default()
+	named := di.Default("ditest-named")
+
+	t.Run("isolated", func(t *testing.T) {
+		app := Isolated(t)
+		app.Bind((*Mailer)(nil), &MockMailer{})
+
+		if di.Default() != app {
+			t.Error("Expected Isolated to return the default container")
+		}
+		if app == outer || di.Default("ditest-named") == named {
+			t.Error("Expected fresh default and named instances")
+		}
+	})
+
+	if di.Default() != outer || di.Default("ditest-named") != named {
+		t.Error("Expected the previous instances to be restored")
+	}
+}
@@ -548,6 +548,62 @@ func TestEndUser_ConcurrentBindAndMake(t *testing.T) {
 	}
 }
 
+func TestEndUser_SlowBindFuncDoesNotBlockOtherMakes(t *testing.T) {
+	c := New()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		close(entered)
+		<-release
+		return &MockUserService{Name: "Slow"}
+	})
+	c.Bind((*EmailService)(nil), func(a *App) interface{} {
+		return &MockEmailService{}
+	})
+
+	go c.Make((*UserService)(nil))
+	<-entered
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		c.Make((*EmailService)(nil))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Make blocked behind a slow BindFunc on another goroutine")
+	}
+}
+
+func TestEndUser_ConcurrentMakeSameTypeNotCircular(t *testing.T) {
+	c := New()
+
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		arrived.Done()
+		arrived.Wait()
+		return &MockUserService{Name: "Parallel"}
+	})
+
+	errs := make(chan interface{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer func() { errs <- recover() }()
+			c.Make((*UserService)(nil))
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if r := <-errs; r != nil {
+			t.Errorf("Parallel resolutions of one type should not be circular: %v", r)
+		}
+	}
+}
+
 func TestEndUser_BindFuncCallsMake(t *testing.T) {
 	c := New()
 
@@ -568,6 +624,62 @@ func TestEndUser_BindFuncCallsMake(t *testing.T) {
 	}
 }
 
+func TestEndUser_BindFuncBindsOnResolver(t *testing.T) {
+	c := New()
+
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		a.Bind((*EmailService)(nil), &MockEmailService{})
+		return &MockUserService{Name: "Nested"}
+	})
+
+	c.Make((*UserService)(nil))
+
+	if _, ok := c.Make((*EmailService)(nil)).(*MockEmailService); !ok {
+		t.Error("Bind from inside a BindFunc should register on the container")
+	}
+}
+
+func TestEndUser_BindFuncResolverOutlivesCall(t *testing.T) {
+	c := New()
+
+	var kept *App
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		kept = a
+		return &MockUserService{Name: "Kept"}
+	})
+	c.Bind((*EmailService)(nil), &MockEmailService{})
+
+	c.Make((*UserService)(nil))
+
+	done := make(chan interface{})
+	go func() {
+		done <- kept.Make((*EmailService)(nil))
+	}()
+	if _, ok := (<-done).(*MockEmailService); !ok {
+		t.Error("Resolver retained past its call should behave like the container")
+	}
+}
+
+type ContainerHolder struct {
+	Container *App `di:""`
+}
+
+func TestEndUser_DiTagInjectsContainerNotResolver(t *testing.T) {
+	c := New()
+
+	var holder *ContainerHolder
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		holder = a.Make(&ContainerHolder{}).(*ContainerHolder)
+		return &MockUserService{}
+	})
+
+	c.Make((*UserService)(nil))
+
+	if holder.Container != c {
+		t.Error("di tag inside a BindFunc should inject the container, not the per-call resolver")
+	}
+}
+
 type StructWithNewAndEmptyInjectPrimitive struct {
 	Count int `inject:""`
 }
@@ -0,0 +1,74 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// embeddedStruct reports whether f is an embedded struct, by value or
+// pointer, whose tags should be processed in place, and returns the
+// addressable struct to process. A nil embedded pointer is allocated first
+// if the struct has tags to process, and otherwise left nil. It is also left
+// nil when its type is among outer, the structs enclosing f, since a type
+// embedding a pointer to itself would otherwise be allocated forever.
+// Tagging the embedded field inject:"-" opts out: it is reported with an
+// invalid Value, and must be left untouched.
+func (A *App) embeddedStruct(f reflect.StructField, field reflect.Value, outer []reflect.Type) (reflect.Value, bool) {
+	t := f.Type
+	if !f.Anonymous || resolveTypePtr(t).Kind() != reflect.Struct || isOptionalType(t) || A.isLiteralType(t) {
+		return reflect.Value{}, false
+	}
+	if f.Tag.Get("inject") == "-" {
+		return reflect.Value{}, true
+	}
+
+	if t.Kind() != reflect.Ptr {
+		// Exported fields promoted through an unexported embedded struct
+		// remain settable, so field itself need not be
+		return field, true
+	}
+	if settable, ok := A.settableField(field); ok {
+		field = settable
+	}
+	if field.IsNil() {
+		if !field.CanSet() || !hasInjectionTags(t.Elem()) || containsType(outer, t.Elem()) {
+			return reflect.Value{}, true
+		}
+		field.Set(reflect.New(t.Elem()))
+	}
+	return field.Elem(), true
+}
+
+// taggedTypes caches hasInjectionTags by struct type.
+var taggedTypes sync.Map
+
+// hasInjectionTags reports whether struct type t, or a struct embedded in
+// it, has a field with an inject, di, env or config tag.
+func hasInjectionTags(t reflect.Type) bool {
+	if v, ok := taggedTypes.Load(t); ok {
+		return v.(bool)
+	}
+	tagged := findInjectionTags(t, make(map[reflect.Type]bool))
+	taggedTypes.Store(t, tagged)
+	return tagged
+}
+
+func findInjectionTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		for _, tag := range []string{"inject", "di", "env", "config"} {
+			if _, ok := f.Tag.Lookup(tag); ok {
+				return true
+			}
+		}
+		if et := resolveTypePtr(f.Type); f.Anonymous && et.Kind() == reflect.Struct && findInjectionTags(et, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package di
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type EmbeddedLogger interface {
+	Log() string
+}
+
+type EmbeddedStdLogger struct{}
+
+func (e *EmbeddedStdLogger) Log() string { return "std" }
+
+type EmbeddedBase struct {
+	Logger EmbeddedLogger `inject:""`
+	Region string         `inject:"eu"`
+}
+
+type EmbeddedHandler struct {
+	EmbeddedBase
+	Name string `inject:"orders"`
+}
+
+type EmbeddedPtrHandler struct {
+	*EmbeddedBase
+}
+
+type EmbeddedDeepHandler struct {
+	EmbeddedHandler
+}
+
+type EmbeddedOptOutHandler struct {
+	EmbeddedBase `inject:"-"`
+	Name         string `inject:"opt-out"`
+}
+
+type embeddedInner struct {
+	Region string `inject:"inner"`
+}
+
+type EmbeddedUnexportedHandler struct {
+	embeddedInner
+}
+
+type EmbeddedCtorHandler struct {
+	*EmbeddedBase
+}
+
+func (e EmbeddedCtorHandler) New() *EmbeddedCtorHandler {
+	return &EmbeddedCtorHandler{EmbeddedBase: &EmbeddedBase{Region: "preset"}}
+}
+
+type EmbeddedUntaggedHandler struct {
+	*bytes.Buffer
+	Name string `inject:"untagged"`
+}
+
+type EmbeddedWhenLogger struct{}
+
+func (e *EmbeddedWhenLogger) Log() string { return "when" }
+
+func newEmbeddedApp() *App {
+	c := New()
+	c.Bind((*EmbeddedLogger)(nil), &EmbeddedStdLogger{})
+	return c
+}
+
+func TestEmbedded_ValueStructInjectedInPlace(t *testing.T) {
+	h := newEmbeddedApp().Make(&EmbeddedHandler{}).(*EmbeddedHandler)
+
+	if h.Logger == nil || h.Logger.Log() != "std" {
+		t.Error("Expected embedded Logger to be injected")
+	}
+	if h.Region != "eu" || h.Name != "orders" {
+		t.Errorf("Expected eu/orders, got %s/%s", h.Region, h.Name)
+	}
+}
+
+func TestEmbedded_PointerStructAllocated(t *testing.T) {
+	h := newEmbeddedApp().Make(EmbeddedPtrHandler{}).(EmbeddedPtrHandler)
+
+	if h.EmbeddedBase == nil || h.Logger == nil || h.Region != "eu" {
+		t.Error("Expected nil embedded pointer to be allocated and injected")
+	}
+}
+
+func TestEmbedded_UntaggedPointerLeftNil(t *testing.T) {
+	c := newEmbeddedApp()
+	h := c.Make(&EmbeddedUntaggedHandler{}).(*EmbeddedUntaggedHandler)
+	if h.Buffer != nil {
+		t.Error("Expected embedded pointer without tags to stay nil")
+	}
+	if h.Name != "untagged" {
+		t.Errorf("Expected untagged, got %s", h.Name)
+	}
+
+	c.Bind(&EmbeddedUntaggedHandler{}, func(a *App) interface{} { return &EmbeddedUntaggedHandler{} })
+	if c.Make(&EmbeddedUntaggedHandler{}).(*EmbeddedUntaggedHandler).Buffer != nil {
+		t.Error("Expected BindFunc result's embedded pointer to stay nil")
+	}
+}
+
+func TestEmbedded_Nested(t *testing.T) {
+	h := newEmbeddedApp().Make(&EmbeddedDeepHandler{}).(*EmbeddedDeepHandler)
+
+	if h.Logger == nil || h.Region != "eu" || h.Name != "orders" {
+		t.Error("Expected tags two levels down to be processed")
+	}
+}
+
+func TestEmbedded_OptOut(t *testing.T) {
+	h := newEmbeddedApp().Make(&EmbeddedOptOutHandler{}).(*EmbeddedOptOutHandler)
+
+	if h.Logger != nil || h.Region != "" {
+		t.Error("Expected embedded struct tagged inject:\"-\" to be left alone")
+	}
+	if h.Name != "opt-out" {
+		t.Errorf("Expected opt-out, got %s", h.Name)
+	}
+}
+
+type EmbeddedNode struct {
+	*EmbeddedNode
+	Delay time.Duration `inject:"1s"`
+}
+
+func TestEmbedded_SelfPointerNotAllocated(t *testing.T) {
+	c := New()
+	n := c.Make(&EmbeddedNode{}).(*EmbeddedNode)
+	if n.EmbeddedNode != nil || n.Delay != time.Second {
+		t.Errorf("Expected the outer node filled and the embedded one left nil, got %+v", n)
+	}
+
+	c.Bind(&EmbeddedNode{}, func(a *App) interface{} { return &EmbeddedNode{EmbeddedNode: &EmbeddedNode{}} })
+	n = c.Make(&EmbeddedNode{}).(*EmbeddedNode)
+	if n.EmbeddedNode.Delay != time.Second || n.EmbeddedNode.EmbeddedNode != nil {
+		t.Error("Expected an existing embedded node filled without allocating another")
+	}
+}
+
+func TestEmbedded_UnexportedTypePromotesFields(t *testing.T) {
+	h := New().Make(&EmbeddedUnexportedHandler{}).(*EmbeddedUnexportedHandler)
+
+	if h.Region != "inner" {
+		t.Errorf("Expected inner, got %s", h.Region)
+	}
+}
+
+func TestEmbedded_AfterNew(t *testing.T) {
+	h := newEmbeddedApp().Make(&EmbeddedCtorHandler{}).(*EmbeddedCtorHandler)
+
+	if h.Logger == nil {
+		t.Error("Expected embedded field to be injected after New()")
+	}
+	if h.Region != "eu" {
+		t.Errorf("inject tags overwrite after New(), expected eu, got %s", h.Region)
+	}
+}
+
+func TestEmbedded_WhenAndMakeWithApplyToPromotedFields(t *testing.T) {
+	c := newEmbeddedApp()
+	c.When(&EmbeddedHandler{}).Needs((*EmbeddedLogger)(nil)).Give(&EmbeddedWhenLogger{})
+
+	h := c.MakeWith(&EmbeddedHandler{}, map[string]interface{}{"Region": "us"}).(*EmbeddedHandler)
+
+	if h.Logger.Log() != "when" {
+		t.Errorf("Expected When rule of the outer type to apply, got %s", h.Logger.Log())
+	}
+	if h.Region != "us" {
+		t.Errorf("Expected MakeWith value us, got %s", h.Region)
+	}
+}
@@ -0,0 +1,100 @@
+package di
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvSource looks up environment variables for env struct tags.
+type EnvSource interface {
+	LookupEnv(string) (string, bool)
+}
+
+// EnvMap is an EnvSource backed by a map, useful in tests.
+type EnvMap map[string]string
+
+// LookupEnv returns the value stored under key.
+func (e EnvMap) LookupEnv(key string) (string, bool) {
+	v, ok := e[key]
+	return v, ok
+}
+
+// osEnv is the EnvSource reading the process environment.
+type osEnv struct{}
+
+func (osEnv) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// envTag is a parsed env:"NAME,default=value,required" tag.
+type envTag struct {
+	name       string
+	def        string
+	hasDefault bool
+	required   bool
+}
+
+// parseEnvTag splits an env tag into its variable name and options. Since
+// default values may contain commas, default= consumes the rest of the tag
+// apart from a trailing required option.
+func parseEnvTag(tag string) envTag {
+	parts := strings.Split(tag, ",")
+	e := envTag{name: strings.TrimSpace(parts[0])}
+	if e.name == "" {
+		panic(fmt.Sprintf("env tag %q has no variable name", tag))
+	}
+
+	for i := 1; i < len(parts); i++ {
+		opt := strings.TrimSpace(parts[i])
+		switch {
+		case strings.HasPrefix(opt, "default="):
+			def := parts[i:]
+			for len(def) > 1 && strings.TrimSpace(def[len(def)-1]) == "required" {
+				e.required = true
+				def = def[:len(def)-1]
+			}
+			rest := strings.TrimLeft(strings.Join(def, ","), " ")
+			e.def = strings.TrimPrefix(rest, "default=")
+			e.hasDefault = true
+			return e
+		case opt == "required":
+			e.required = true
+		default:
+			panic(fmt.Sprintf("unknown env tag option %q in %q", opt, tag))
+		}
+	}
+
+	return e
+}
+
+// setByEnvTag sets field f from the environment variable named by its env
+// tag, at resolution time. A MakeWith value for the field takes precedence.
+// Unset variables panic when required, fall back to the default option, and
+// otherwise leave the field untouched. A variable that is set but empty
+// takes the default, if the tag has one.
+func (A *App) setByEnvTag(f reflect.StructField, field reflect.Value, tag string, injectables map[string]interface{}) {
+	pv, pe := injectables[f.Name]
+	if pe && pv != nil && reflect.ValueOf(pv).Type().AssignableTo(field.Type()) {
+		field.Set(reflect.ValueOf(pv))
+		return
+	}
+
+	e := parseEnvTag(tag)
+	v, ok := A.env.LookupEnv(e.name)
+	if ok && v == "" && e.hasDefault {
+		v = e.def
+	}
+	if !ok {
+		if e.required {
+			panic(fmt.Sprintf("required environment variable %s is not set for field %s", e.name, f.Name))
+		}
+		if !e.hasDefault {
+			return
+		}
+		v = e.def
+	}
+
+	A.setByTagValue(field, v)
+}
@@ -0,0 +1,178 @@
+package di
+
+import (
+	"testing"
+)
+
+type EnvConfig struct {
+	Port    int     `env:"PORT,default=8080"`
+	Host    string  `env:"HOST,required"`
+	Debug   bool    `env:"DEBUG"`
+	Ratio   float64 `env:"RATIO,default=0.5"`
+	Untyped string
+}
+
+type EnvConfigWithNew struct {
+	Region string `env:"REGION"`
+	Zone   string `env:"ZONE,default=a,b"`
+}
+
+func (e EnvConfigWithNew) New() *EnvConfigWithNew {
+	return &EnvConfigWithNew{Region: "from-new"}
+}
+
+type EnvRequiredDefault struct {
+	Port  int      `env:"PORT,default=8080,required"`
+	Hosts []string `env:"HOSTS,required,default=a,b"`
+}
+
+type EnvBadOption struct {
+	Port int `env:"PORT,optional"`
+}
+
+type EnvBadName struct {
+	Port int `env:",default=1"`
+}
+
+func TestEnv_ReadsFromSource(t *testing.T) {
+	c := New(AppConfig{Env: EnvMap{"PORT": "9090", "HOST": "example.com", "DEBUG": "true"}})
+
+	cfg := c.Make(&EnvConfig{}).(*EnvConfig)
+
+	if cfg.Port != 9090 {
+		t.Errorf("Expected 9090, got %d", cfg.Port)
+	}
+	if cfg.Host != "example.com" {
+		t.Errorf("Expected example.com, got %s", cfg.Host)
+	}
+	if !cfg.Debug {
+		t.Error("Expected Debug to be true")
+	}
+	if cfg.Ratio != 0.5 {
+		t.Errorf("Expected default 0.5, got %v", cfg.Ratio)
+	}
+}
+
+func TestEnv_ReadsProcessEnvironment(t *testing.T) {
+	t.Setenv("PORT", "7070")
+	t.Setenv("HOST", "localhost")
+
+	cfg := New().Make(EnvConfig{}).(EnvConfig)
+
+	if cfg.Port != 7070 || cfg.Host != "localhost" {
+		t.Errorf("Expected 7070 and localhost from the environment, got %d and %s", cfg.Port, cfg.Host)
+	}
+}
+
+func TestEnv_ReadAtResolutionTime(t *testing.T) {
+	env := EnvMap{"HOST": "first"}
+	c := New(AppConfig{Env: env})
+
+	first := c.Make(&EnvConfig{}).(*EnvConfig)
+	env["HOST"] = "second"
+	second := c.Make(&EnvConfig{}).(*EnvConfig)
+
+	if first.Host != "first" || second.Host != "second" {
+		t.Errorf("Expected env to be read on each Make, got %s then %s", first.Host, second.Host)
+	}
+}
+
+func TestEnv_RequiredMissingPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for missing required environment variable")
+		}
+	}()
+
+	New(AppConfig{Env: EnvMap{}}).Make(&EnvConfig{})
+}
+
+func TestEnv_EmptyTakesDefault(t *testing.T) {
+	cfg := New(AppConfig{Env: EnvMap{"PORT": "", "HOST": "", "RATIO": ""}}).Make(&EnvConfig{}).(*EnvConfig)
+	if cfg.Port != 8080 || cfg.Ratio != 0.5 {
+		t.Errorf("Expected the defaults for empty variables, got %d and %v", cfg.Port, cfg.Ratio)
+	}
+	if cfg.Host != "" {
+		t.Errorf("Expected an empty variable without a default to be used as is, got %q", cfg.Host)
+	}
+}
+
+func TestEnv_RequiredWithDefault(t *testing.T) {
+	cfg := New(AppConfig{Env: EnvMap{"PORT": "", "HOSTS": "c,d"}}).Make(&EnvRequiredDefault{}).(*EnvRequiredDefault)
+	if cfg.Port != 8080 {
+		t.Errorf("Expected the default for an empty required variable, got %d", cfg.Port)
+	}
+	if len(cfg.Hosts) != 2 || cfg.Hosts[0] != "c" {
+		t.Errorf("Expected the variable's value, got %v", cfg.Hosts)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for unset variable marked required after default=")
+		}
+	}()
+	New(AppConfig{Env: EnvMap{"HOSTS": "c"}}).Make(&EnvRequiredDefault{})
+}
+
+func TestEnv_InvalidValuePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for unparseable environment value")
+		}
+	}()
+
+	New(AppConfig{Env: EnvMap{"HOST": "h", "PORT": "eighty"}}).Make(&EnvConfig{})
+}
+
+func TestEnv_MakeWithOverrides(t *testing.T) {
+	c := New(AppConfig{Env: EnvMap{"HOST": "env"}})
+
+	cfg := c.MakeWith(&EnvConfig{}, map[string]interface{}{"Host": "override"}).(*EnvConfig)
+
+	if cfg.Host != "override" {
+		t.Errorf("Expected MakeWith to override env, got %s", cfg.Host)
+	}
+}
+
+func TestEnv_AppliedAfterNew(t *testing.T) {
+	c := New(AppConfig{Env: EnvMap{}})
+
+	cfg := c.Make(&EnvConfigWithNew{}).(*EnvConfigWithNew)
+
+	if cfg.Region != "from-new" {
+		t.Errorf("Unset env without default should keep the New() value, got %s", cfg.Region)
+	}
+	if cfg.Zone != "a,b" {
+		t.Errorf("Expected default to keep its commas, got %s", cfg.Zone)
+	}
+
+	c = New(AppConfig{Env: EnvMap{"REGION": "eu"}})
+	cfg = c.Make(&EnvConfigWithNew{}).(*EnvConfigWithNew)
+
+	if cfg.Region != "eu" {
+		t.Errorf("Set env should overwrite the New() value, got %s", cfg.Region)
+	}
+}
+
+func TestEnv_ScopeInheritsSource(t *testing.T) {
+	c := New(AppConfig{Env: EnvMap{"HOST": "scoped"}})
+
+	cfg := c.Scope().Make(&EnvConfig{}).(*EnvConfig)
+
+	if cfg.Host != "scoped" {
+		t.Errorf("Scope should use its parent's env source, got %s", cfg.Host)
+	}
+}
+
+func TestEnv_BadTagsPanic(t *testing.T) {
+	for _, v := range []interface{}{&EnvBadOption{}, &EnvBadName{}} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for malformed env tag on %T", v)
+				}
+			}()
+			New(AppConfig{Env: EnvMap{"PORT": "1"}}).Make(v)
+		}()
+	}
+}
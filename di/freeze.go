@@ -0,0 +1,67 @@
+package di
+
+import (
+	"fmt"
+)
+
+// FrozenContainerError is raised, as a panic, by calls that would change the
+// bindings of a frozen container, and returned by Dispose.
+type FrozenContainerError struct {
+	Op string // the rejected method, e.g. "Bind"
+}
+
+func (e *FrozenContainerError) Error() string {
+	return fmt.Sprintf("container is frozen, %s is not allowed", e.Op)
+}
+
+// Freeze seals the container once bootstrapping is done. Bind, Singleton,
+// When(...).Needs(...).Give(...) and the other registration methods panic
+// with a *FrozenContainerError from then on, while Make keeps working and
+// no longer locks the registries once every deferred provider has been
+// loaded. Deferred providers still load on first use, binding through the
+// resolver they are given. Scopes of a frozen container are not frozen, so
+// per-request bindings keep working.
+func (A *App) Freeze() AppInterface {
+	// Writes already past checkFrozen hold appMu and re-check the flag
+	A.appMu.Lock()
+	A.frozen.Store(true)
+	A.appMu.Unlock()
+	return A
+}
+
+// checkFrozen panics when the container is frozen.
+func (A *App) checkFrozen(op string) {
+	if A.frozen.Load() && !A.loadingDeferred() {
+		panic(&FrozenContainerError{Op: op})
+	}
+}
+
+// writeLock locks the container's maps for writing and returns the unlock.
+// It panics with a *FrozenContainerError for op when the container is
+// frozen, so a write that passed checkFrozen before Freeze cannot land after
+// it.
+func (A *App) writeLock(op string) func() {
+	A.appMu.Lock()
+	if A.frozen.Load() && !A.loadingDeferred() {
+		A.appMu.Unlock()
+		panic(&FrozenContainerError{Op: op})
+	}
+	return A.appMu.Unlock
+}
+
+// loadingDeferred reports whether A is the resolver of a deferred provider
+// that is still loading, which may register on a frozen container.
+func (A *App) loadingDeferred() bool {
+	return A.loading != nil && !A.loading.loaded.Load()
+}
+
+// readLock read-locks the container's maps and returns the matching unlock.
+// Frozen containers with no deferred provider left to load can no longer
+// change, so they are read without locking.
+func (A *App) readLock() func() {
+	if A.frozen.Load() && A.pending.Load() == 0 {
+		return func() {}
+	}
+	A.appMu.RLock()
+	return A.appMu.RUnlock
+}
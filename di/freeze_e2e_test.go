@@ -0,0 +1,198 @@
+package di
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type FreezeService interface {
+	Name() string
+}
+
+type FreezeImpl struct{}
+
+func (f *FreezeImpl) Name() string { return "impl" }
+
+type FreezeOther struct{}
+
+func (f *FreezeOther) Name() string { return "other" }
+
+type FreezeConsumer struct {
+	Service FreezeService `inject:""`
+}
+
+type FreezeDeferredProvider struct {
+	registered bool
+}
+
+func (p *FreezeDeferredProvider) Provides() []interface{} {
+	return []interface{}{&FreezeOther{}}
+}
+
+func (p *FreezeDeferredProvider) Register(a *App) {
+	p.registered = true
+	a.Bind(&FreezeOther{}, func(a *App) interface{} { return &FreezeOther{} })
+}
+
+func (p *FreezeDeferredProvider) Boot(a *App) error { return nil }
+
+func expectFrozen(t *testing.T, op string, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		var err *FrozenContainerError
+		e, _ := recover().(error)
+		if !errors.As(e, &err) || err.Op != op {
+			t.Errorf("Expected FrozenContainerError for %s, got %v", op, e)
+		}
+	}()
+	f()
+}
+
+func TestFreeze_RejectsRegistration(t *testing.T) {
+	c := New()
+	c.Bind((*FreezeService)(nil), &FreezeImpl{})
+	c.Freeze()
+
+	expectFrozen(t, "Bind", func() { c.Bind((*FreezeService)(nil), &FreezeOther{}) })
+	expectFrozen(t, "Bind", func() { c.Bind((*FreezeService)(nil), nil) })
+	expectFrozen(t, "Singleton", func() { c.Singleton(&FreezeImpl{}) })
+	expectFrozen(t, "Give", func() {
+		c.When(&FreezeConsumer{}).Needs((*FreezeService)(nil)).Give(&FreezeOther{})
+	})
+	expectFrozen(t, "BindDefault", func() { c.BindDefault((*FreezeService)(nil), &FreezeOther{}) })
+	expectFrozen(t, "RegisterConverter", func() { c.RegisterConverter(reflect.TypeOf(0), nil) })
+	expectFrozen(t, "RegisterProvider", func() { c.RegisterProvider(&FreezeDeferredProvider{}) })
+
+	var err *FrozenContainerError
+	if !errors.As(c.Dispose(), &err) {
+		t.Error("Expected Dispose to return a FrozenContainerError")
+	}
+}
+
+func TestFreeze_MakeKeepsWorking(t *testing.T) {
+	c := New()
+	c.Bind((*FreezeService)(nil), &FreezeImpl{})
+	c.Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Make(&FreezeConsumer{}).(*FreezeConsumer).Service.Name() != "impl" {
+				t.Error("Expected Make to resolve after Freeze")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFreeze_ScopesStayWritable(t *testing.T) {
+	c := New()
+	c.Bind((*FreezeService)(nil), &FreezeImpl{})
+	c.Freeze()
+
+	s := c.Scope()
+	s.Bind((*FreezeService)(nil), &FreezeOther{})
+	if s.Make((*FreezeService)(nil)).(FreezeService).Name() != "other" {
+		t.Error("Expected scope of a frozen container to accept bindings")
+	}
+	if c.Make((*FreezeService)(nil)).(FreezeService).Name() != "impl" {
+		t.Error("Expected frozen parent to be unchanged")
+	}
+}
+
+func TestFreeze_DeferredProvidersLoadOnFirstUse(t *testing.T) {
+	c := New()
+	p := &FreezeDeferredProvider{}
+	c.RegisterProvider(p)
+	c.Freeze()
+
+	if p.registered {
+		t.Fatal("Expected Freeze to leave deferred providers pending")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Make(&FreezeOther{}) == nil {
+				t.Error("Expected the deferred binding to resolve")
+			}
+			c.Make(&FreezeImpl{})
+		}()
+	}
+	wg.Wait()
+
+	if !p.registered {
+		t.Error("Expected the deferred provider to load after Freeze")
+	}
+	defer func() {
+		if _, ok := recover().(*FrozenContainerError); !ok {
+			t.Error("Expected the container to stay frozen after the load")
+		}
+	}()
+	c.Bind(&FreezeOther{}, &FreezeOther{})
+}
+
+func TestFreeze_RejectsRegistrationInFlight(t *testing.T) {
+	c := New()
+	building := make(chan struct{})
+	frozen := make(chan struct{})
+	done := make(chan interface{})
+
+	go func() {
+		defer func() { done <- recover() }()
+		c.Singleton((*FreezeService)(nil), func(a *App) interface{} {
+			close(building)
+			<-frozen
+			return &FreezeImpl{}
+		})
+	}()
+
+	<-building
+	c.Freeze()
+	close(frozen)
+
+	var fe *FrozenContainerError
+	if err, _ := (<-done).(error); !errors.As(err, &fe) || fe.Op != "Singleton" {
+		t.Fatalf("expected FrozenContainerError for Singleton, got %v", err)
+	}
+	if c.hasBinding(bindingKey(reflect.TypeOf((*FreezeService)(nil)).Elem())) {
+		t.Error("expected the binding not to land after Freeze")
+	}
+}
+
+type FreezeLeakyProvider struct {
+	app *App
+}
+
+func (p *FreezeLeakyProvider) Provides() []interface{} {
+	return []interface{}{&FreezeOther{}}
+}
+
+func (p *FreezeLeakyProvider) Register(a *App) {
+	p.app = a
+	a.Bind(&FreezeOther{}, func(a *App) interface{} { return &FreezeOther{} })
+}
+
+func (p *FreezeLeakyProvider) Boot(a *App) error { return nil }
+
+func TestFreeze_DeferredResolverFrozenAfterLoad(t *testing.T) {
+	c := New()
+	p := &FreezeLeakyProvider{}
+	c.RegisterProvider(p)
+	c.Freeze()
+	c.Make(&FreezeOther{})
+
+	defer func() {
+		if _, ok := recover().(*FrozenContainerError); !ok {
+			t.Error("Expected the provider's resolver to be frozen once loaded")
+		}
+	}()
+	p.app.Bind(&FreezeImpl{}, &FreezeImpl{})
+}
@@ -0,0 +1,70 @@
+// Package http provides net/http middleware that gives every request its own
+// scope of a di container, with the request and response writer bound into it.
+package http
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"reflect"
+
+	"github.com/daforester/go-di-container/di"
+)
+
+type scopeKey struct{}
+
+var handlerType = reflect.TypeOf((*nethttp.Handler)(nil)).Elem()
+
+// Middleware returns middleware that creates a scope of app for each request.
+// The scope is stored in the request context, has the *http.Request and
+// http.ResponseWriter bound as singletons, and is disposed once the next
+// handler returns.
+func Middleware(app di.AppInterface) func(nethttp.Handler) nethttp.Handler {
+	if app == nil {
+		panic("Middleware() requires a non-nil container")
+	}
+
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			scope := app.Scope()
+			defer scope.Dispose()
+
+			r = r.WithContext(context.WithValue(r.Context(), scopeKey{}, scope))
+			scope.Singleton(r)
+			scope.Singleton((*nethttp.ResponseWriter)(nil), w)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromContext returns the request scope stored in ctx by Middleware, or nil.
+func FromContext(ctx context.Context) di.AppInterface {
+	scope, _ := ctx.Value(scopeKey{}).(di.AppInterface)
+	return scope
+}
+
+// FromRequest returns the scope Middleware created for r, or nil.
+func FromRequest(r *nethttp.Request) di.AppInterface {
+	return FromContext(r.Context())
+}
+
+// Handler returns a handler that builds a fresh h from the request scope via
+// MakeContext on every request and serves the request with it. h is only
+// used for its type, e.g. Handler(&UserHandler{}), and must implement
+// http.Handler. Requests that did not pass through Middleware panic.
+func Handler(h interface{}) nethttp.Handler {
+	t := reflect.TypeOf(h)
+	if t == nil || !t.Implements(handlerType) {
+		panic(fmt.Sprintf("Handler() requires a type implementing http.Handler, got %s", t))
+	}
+
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		scope := FromRequest(r)
+		if scope == nil {
+			panic(fmt.Sprintf("no request scope found for %s, wrap the handler with Middleware", t))
+		}
+
+		scope.MakeContext(r.Context(), h).(nethttp.Handler).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daforester/go-di-container/di"
+)
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type EnglishGreeter struct{}
+
+func (g *EnglishGreeter) Greet(name string) string { return "Hello " + name }
+
+type GreetHandler struct {
+	Greeter Greeter                `inject:""`
+	Request *nethttp.Request       `inject:""`
+	Writer  nethttp.ResponseWriter `inject:""`
+	Ctx     context.Context        `inject:""`
+	Scope   *di.App                `di:""`
+}
+
+func (h *GreetHandler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	h.Writer.Write([]byte(h.Greeter.Greet(h.Request.URL.Query().Get("name"))))
+}
+
+type closeTracker struct {
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newTestApp() *di.App {
+	app := di.New()
+	app.Bind((*Greeter)(nil), &EnglishGreeter{})
+	return app
+}
+
+func TestMiddleware_BindsRequestAndWriter(t *testing.T) {
+	app := newTestApp()
+
+	var scope di.AppInterface
+	h := Middleware(app)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		scope = FromRequest(r)
+		if scope == nil {
+			t.Fatal("FromRequest should return the request scope")
+		}
+		if scope == app {
+			t.Error("Request scope should not be the container itself")
+		}
+		if scope.Make(&nethttp.Request{}) != r {
+			t.Error("Request scope should resolve *http.Request to the current request")
+		}
+		if scope.Make((*nethttp.ResponseWriter)(nil)) != w {
+			t.Error("Request scope should resolve http.ResponseWriter to the current writer")
+		}
+		if _, ok := scope.Make((*Greeter)(nil)).(*EnglishGreeter); !ok {
+			t.Error("Request scope should inherit the container's bindings")
+		}
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Request bindings should not leak into the container")
+		}
+	}()
+	app.Make((*nethttp.ResponseWriter)(nil))
+}
+
+func TestMiddleware_ScopesAreIndependent(t *testing.T) {
+	app := newTestApp()
+
+	scopes := make(map[di.AppInterface]bool)
+	h := Middleware(app)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		scopes[FromRequest(r)] = true
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if len(scopes) != 2 {
+		t.Errorf("Expected a new scope per request, got %d", len(scopes))
+	}
+}
+
+func TestMiddleware_DisposesScope(t *testing.T) {
+	app := newTestApp()
+
+	tracker := &closeTracker{}
+	h := Middleware(app)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		FromRequest(r).Singleton(tracker)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !tracker.closed {
+		t.Error("Singletons bound in the request scope should be closed at the end of the request")
+	}
+}
+
+func TestFromRequest_WithoutMiddleware(t *testing.T) {
+	if FromRequest(httptest.NewRequest("GET", "/", nil)) != nil {
+		t.Error("FromRequest should return nil without the middleware")
+	}
+}
+
+func TestHandler_BuildsHandlerPerRequest(t *testing.T) {
+	app := newTestApp()
+
+	var handlers []*GreetHandler
+	h := Middleware(app)(Handler(&GreetHandler{}))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/?name=Alice", nil)
+		h.ServeHTTP(rec, req)
+
+		if rec.Body.String() != "Hello Alice" {
+			t.Errorf("Expected \"Hello Alice\", got %q", rec.Body.String())
+		}
+	}
+
+	// Inspect what the handler received
+	inspect := Middleware(app)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		handlers = append(handlers, FromRequest(r).MakeContext(r.Context(), &GreetHandler{}).(*GreetHandler))
+	}))
+	inspect.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if handlers[0].Scope == app || handlers[0].Scope == nil {
+		t.Error("di tag should inject the request scope")
+	}
+	if FromContext(handlers[0].Ctx) != handlers[0].Scope {
+		t.Error("context.Context field should receive the request context")
+	}
+}
+
+func TestHandler_RequiresMiddleware(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when serving without the middleware")
+		}
+	}()
+
+	Handler(&GreetHandler{}).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestHandler_RequiresHandlerType(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for a type that does not implement http.Handler")
+		}
+	}()
+
+	Handler(&EnglishGreeter{})
+}
@@ -0,0 +1,147 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// LoggingApp decorates a container the way code outside the package can,
+// by embedding AppInterface.
+type LoggingApp struct {
+	AppInterface
+	made int
+}
+
+func (l *LoggingApp) Make(a interface{}) interface{} {
+	l.made++
+	return l.AppInterface.Make(a)
+}
+
+// StubWhen implements the When chain without a container.
+type StubWhen struct {
+	needed interface{}
+}
+
+func (s *StubWhen) Needs(a interface{}) NeedsBuilder {
+	s.needed = a
+	return s
+}
+
+func (s *StubWhen) Within() WhenBuilder {
+	return s
+}
+
+func (s *StubWhen) Give(b interface{}) ObjectInterface {
+	return nil
+}
+
+func (s *StubWhen) GiveSingleton(b interface{}, config ...GiveConfig) ObjectInterface {
+	return nil
+}
+
+func (s *StubWhen) GiveFactory(f interface{}) ObjectInterface {
+	return nil
+}
+
+type StubApp struct {
+	AppInterface
+	when *StubWhen
+}
+
+func (s *StubApp) When(a interface{}, more ...interface{}) WhenBuilder {
+	return s.when
+}
+
+type InterfaceAppHolder struct {
+	App AppInterface `di:""`
+}
+
+type InterfaceGreeter struct {
+	Greeting string
+}
+
+func (g *InterfaceGreeter) Greet() string { return g.Greeting }
+
+type InterfaceNamedGreeter interface {
+	Greet() string
+}
+
+var _ AppInterface = (*LoggingApp)(nil)
+
+func TestAppInterface_DecoratorBoundForDiTag(t *testing.T) {
+	c := New()
+	logging := &LoggingApp{AppInterface: c}
+	c.Singleton((*AppInterface)(nil), logging)
+
+	h := c.Make(&InterfaceAppHolder{}).(*InterfaceAppHolder)
+	if h.App != logging {
+		t.Fatal("Expected the bound AppInterface implementation to be injected")
+	}
+
+	h.App.Make(&InterfaceGreeter{})
+	if logging.made != 1 {
+		t.Error("Expected calls to go through the decorator")
+	}
+}
+
+func TestAppInterface_DiTagDefaultsToContainer(t *testing.T) {
+	c := New()
+
+	if c.Make(&InterfaceAppHolder{}).(*InterfaceAppHolder).App != c {
+		t.Error("Expected the container when no AppInterface is bound")
+	}
+}
+
+func TestAppInterface_WhenBuilderImplementable(t *testing.T) {
+	stub := &StubApp{AppInterface: New(), when: &StubWhen{}}
+	var app AppInterface = stub
+
+	app.When(&InterfaceAppHolder{}).Needs((*AppInterface)(nil)).Give(&LoggingApp{})
+	if stub.when.needed == nil {
+		t.Error("Expected the stub When chain to be used")
+	}
+}
+
+func TestAppInterface_OverrideRejectsForeignNeedsBuilder(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "Override()") {
+			t.Errorf("Expected Override to reject a foreign NeedsBuilder, got %v", r)
+		}
+	}()
+	New().Override((&StubWhen{}).Needs((*AppInterface)(nil)), &LoggingApp{})
+}
+
+func TestAppInterface_OverrideRejectsOtherContainersChain(t *testing.T) {
+	a, b := New(), New()
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "same container") {
+			t.Errorf("Expected Override to reject another container's chain, got %v", r)
+		}
+		if _, ok := b.hintsFor(reflect.TypeOf(&InterfaceAppHolder{})); ok {
+			t.Error("Expected the other container to be left unchanged")
+		}
+	}()
+	a.Override(b.When(&InterfaceAppHolder{}).Needs((*AppInterface)(nil)), &LoggingApp{})
+}
+
+func TestInterfaceBindFunc(t *testing.T) {
+	c := New()
+	c.Bind(&InterfaceGreeter{}, func(a AppInterface) interface{} {
+		if a.Context() == nil {
+			t.Error("Expected the resolver as AppInterface")
+		}
+		return &InterfaceGreeter{Greeting: "hi"}
+	})
+	c.Singleton((*InterfaceNamedGreeter)(nil), InterfaceBindFunc(func(a AppInterface) interface{} {
+		return a.Make(&InterfaceGreeter{})
+	}))
+
+	if c.Make(&InterfaceGreeter{}).(*InterfaceGreeter).Greeting != "hi" {
+		t.Error("Expected func(AppInterface) interface{} to be accepted as a BindFunc")
+	}
+	if c.Make((*InterfaceNamedGreeter)(nil)).(InterfaceNamedGreeter).Greet() != "hi" {
+		t.Error("Expected InterfaceBindFunc to be accepted by Singleton")
+	}
+}
@@ -0,0 +1,149 @@
+package di
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isLiteralType reports whether setByTagValue can parse a value of type t
+// from a string.
+func (A *App) isLiteralType(t reflect.Type) bool {
+	if A.converter(t) != nil || t == durationType || isTextType(t) || isPrimitiveKind(t.Kind()) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return A.isLiteralType(t.Elem())
+	case reflect.Map:
+		return A.isLiteralType(t.Key()) && A.isLiteralType(t.Elem())
+	}
+	return false
+}
+
+// isTextType reports whether t, or a pointer to t, implements
+// encoding.TextUnmarshaler.
+func isTextType(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// setByTagValue parses v into f. Converters registered for f's type win;
+// otherwise handles encoding.TextUnmarshaler implementations (time.Time as
+// RFC3339, net.IP, ...), time.Duration, primitives including named ones,
+// comma-separated slices and k=v,k2=v2 maps.
+func (A *App) setByTagValue(f reflect.Value, v string) {
+	t := f.Type()
+
+	if c := A.converter(t); c != nil {
+		A.setByConverter(f, c, v)
+		return
+	}
+
+	if isTextType(t) {
+		target := f
+		if t.Kind() == reflect.Ptr {
+			target = reflect.New(t.Elem())
+		} else {
+			target = f.Addr()
+		}
+		if err := target.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+			panic(err)
+		}
+		if t.Kind() == reflect.Ptr {
+			f.Set(target)
+		}
+		return
+	}
+
+	if t == durationType {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			panic(err)
+		}
+		f.SetInt(int64(d))
+		return
+	}
+
+	// Parsing for inject values on primitives
+	switch t.Kind() {
+	case reflect.String:
+		f.SetString(v)
+		return
+	case reflect.Bool:
+		iv, err := strconv.ParseBool(v)
+		if err != nil {
+			panic(err)
+		}
+		f.SetBool(iv)
+		return
+	case reflect.Float32, reflect.Float64:
+		iv, err := strconv.ParseFloat(v, t.Bits())
+		if err != nil {
+			panic(err)
+		}
+		f.SetFloat(iv)
+		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		iv, err := strconv.ParseInt(v, 10, t.Bits())
+		if err != nil {
+			panic(err)
+		}
+		f.SetInt(iv)
+		return
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		iv, err := strconv.ParseUint(v, 10, t.Bits())
+		if err != nil {
+			panic(err)
+		}
+		f.SetUint(iv)
+		return
+	case reflect.Slice:
+		items := splitLiteral(v)
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			A.setByTagValue(slice.Index(i), item)
+		}
+		f.Set(slice)
+		return
+	case reflect.Map:
+		items := splitLiteral(v)
+		m := reflect.MakeMapWithSize(t, len(items))
+		for _, item := range items {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				panic(fmt.Sprintf("can not parse map entry %q, expected key=value", item))
+			}
+			mk := reflect.New(t.Key()).Elem()
+			A.setByTagValue(mk, strings.TrimSpace(key))
+			mv := reflect.New(t.Elem()).Elem()
+			A.setByTagValue(mv, strings.TrimSpace(value))
+			m.SetMapIndex(mk, mv)
+		}
+		f.Set(m)
+		return
+	}
+
+	// Can not handle parsing for this type
+	panic(fmt.Sprintf("can not initialize value for kind %s", t.Kind()))
+}
+
+// splitLiteral splits a comma-separated tag value, trimming spaces. An
+// empty value has no items.
+func splitLiteral(v string) []string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	items := strings.Split(v, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
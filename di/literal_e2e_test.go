@@ -0,0 +1,175 @@
+package di
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type LiteralLevel int
+
+const (
+	LiteralLevelInfo LiteralLevel = iota
+	LiteralLevelWarn
+)
+
+func (l *LiteralLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "info":
+		*l = LiteralLevelInfo
+	case "warn":
+		*l = LiteralLevelWarn
+	default:
+		return fmt.Errorf("unknown level %q", b)
+	}
+	return nil
+}
+
+type LiteralName string
+
+type LiteralTagged struct {
+	Timeout  time.Duration     `inject:"30s"`
+	Started  time.Time         `inject:"2024-03-01T12:00:00Z"`
+	Deadline *time.Time        `inject:"2024-03-02T12:00:00Z"`
+	Hosts    []string          `inject:"a.example, b.example"`
+	Ports    []int             `inject:"80,443"`
+	Weights  map[string]int    `inject:"primary=3, backup=1"`
+	Retries  map[int]string    `inject:"1=fast,2=slow"`
+	Backoffs []time.Duration   `inject:"1s,5s"`
+	Addr     net.IP            `inject:"10.0.0.1"`
+	Level    LiteralLevel      `inject:"warn"`
+	Name     LiteralName       `inject:"named"`
+	Labels   map[string]string `inject:"env=prod"`
+}
+
+type LiteralDual struct {
+	Timeout time.Duration `di:"" inject:"2m"`
+}
+
+type LiteralWithNew struct {
+	Timeout time.Duration `inject:"10ms"`
+}
+
+func (l LiteralWithNew) New() *LiteralWithNew {
+	return &LiteralWithNew{Timeout: time.Hour}
+}
+
+type LiteralEnvConfig struct {
+	Timeout time.Duration `env:"TIMEOUT,default=5s"`
+	Hosts   []string      `env:"HOSTS,default=x,y"`
+}
+
+type LiteralConfig struct {
+	Timeout time.Duration `config:"http.timeout"`
+	Since   time.Time     `config:"http.since"`
+	Hosts   []string      `config:"http.hosts"`
+}
+
+func TestLiteral_RichTagTypes(t *testing.T) {
+	v := New().Make(&LiteralTagged{}).(*LiteralTagged)
+
+	if v.Timeout != 30*time.Second {
+		t.Errorf("Expected 30s, got %s", v.Timeout)
+	}
+	if !v.Started.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected RFC3339 time, got %s", v.Started)
+	}
+	if v.Deadline == nil || v.Deadline.Day() != 2 {
+		t.Errorf("Expected *time.Time to be allocated and parsed, got %v", v.Deadline)
+	}
+	if !reflect.DeepEqual(v.Hosts, []string{"a.example", "b.example"}) {
+		t.Errorf("Expected comma-separated strings, got %v", v.Hosts)
+	}
+	if !reflect.DeepEqual(v.Ports, []int{80, 443}) {
+		t.Errorf("Expected comma-separated ints, got %v", v.Ports)
+	}
+	if !reflect.DeepEqual(v.Weights, map[string]int{"primary": 3, "backup": 1}) {
+		t.Errorf("Expected k=v map, got %v", v.Weights)
+	}
+	if !reflect.DeepEqual(v.Retries, map[int]string{1: "fast", 2: "slow"}) {
+		t.Errorf("Expected int-keyed map, got %v", v.Retries)
+	}
+	if !reflect.DeepEqual(v.Backoffs, []time.Duration{time.Second, 5 * time.Second}) {
+		t.Errorf("Expected durations list, got %v", v.Backoffs)
+	}
+	if !v.Addr.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Expected net.IP via TextUnmarshaler, got %v", v.Addr)
+	}
+	if v.Level != LiteralLevelWarn {
+		t.Errorf("Expected custom enum via TextUnmarshaler, got %d", v.Level)
+	}
+	if v.Name != "named" {
+		t.Errorf("Expected named string type, got %s", v.Name)
+	}
+	if v.Labels["env"] != "prod" {
+		t.Errorf("Expected string map, got %v", v.Labels)
+	}
+}
+
+func TestLiteral_DualTagAndNew(t *testing.T) {
+	c := New()
+
+	if c.Make(&LiteralDual{}).(*LiteralDual).Timeout != 2*time.Minute {
+		t.Error("Dual di+inject tag should parse a duration literal")
+	}
+	if c.Make(&LiteralWithNew{}).(*LiteralWithNew).Timeout != 10*time.Millisecond {
+		t.Error("inject literal should overwrite the New() value")
+	}
+}
+
+func TestLiteral_InvalidValuesPanic(t *testing.T) {
+	bad := []interface{}{
+		&struct {
+			D time.Duration `inject:"soon"`
+		}{},
+		&struct {
+			T time.Time `inject:"yesterday"`
+		}{},
+		&struct {
+			L LiteralLevel `inject:"loud"`
+		}{},
+		&struct {
+			P []int `inject:"1,two"`
+		}{},
+		&struct {
+			M map[string]int `inject:"a=1,b"`
+		}{},
+		&struct {
+			C chan int `inject:"1"`
+		}{},
+	}
+
+	for _, v := range bad {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic parsing literal for %T", v)
+				}
+			}()
+			New().Make(v)
+		}()
+	}
+}
+
+func TestLiteral_EnvAndConfig(t *testing.T) {
+	c := New(AppConfig{Env: EnvMap{"TIMEOUT": "1m"}})
+	c.BindConfig(
+		ConfigMap{"http": map[string]interface{}{"timeout": "15s", "since": "2024-01-01T00:00:00Z"}},
+		ConfigEnv{Env: EnvMap{"HTTP_HOSTS": "a,b,c"}},
+	)
+
+	env := c.Make(&LiteralEnvConfig{}).(*LiteralEnvConfig)
+	if env.Timeout != time.Minute || !reflect.DeepEqual(env.Hosts, []string{"x", "y"}) {
+		t.Errorf("Expected env duration and default list, got %s %v", env.Timeout, env.Hosts)
+	}
+
+	cfg := c.Make(&LiteralConfig{}).(*LiteralConfig)
+	if cfg.Timeout != 15*time.Second || cfg.Since.Year() != 2024 {
+		t.Errorf("Expected config duration and time, got %s %s", cfg.Timeout, cfg.Since)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a", "b", "c"}) {
+		t.Errorf("Expected comma-separated env value to fill a slice, got %v", cfg.Hosts)
+	}
+}
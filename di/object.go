@@ -3,6 +3,7 @@ package di
 import (
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 // Kind classifies what a bound Object wraps.
@@ -31,6 +32,17 @@ type Object struct {
 	Name      string
 	Kind      Kind
 	singleton bool
+	shared    *sharedInstance        // once-guard for singletons built on first use
+	recreate  func(*App) interface{} // how the container built a Singleton's instance
+	build     func(*App) interface{} // set by Clone to re-create the instance on first use
+	perType   *sync.Map              // GiveSingleton: requesting type name -> *Object holding its instance
+}
+
+// sharedInstance holds a lazily built singleton value.
+type sharedInstance struct {
+	mu    sync.Mutex
+	built bool
+	value interface{}
 }
 
 func (o Object) New(v interface{}, k ...Kind) ObjectInterface {
@@ -64,7 +76,7 @@ func (o Object) New(v interface{}, k ...Kind) ObjectInterface {
 	}
 
 	if obj.Kind == Func {
-		if !vType.ConvertibleTo(bindFuncType) {
+		if !isBindFuncType(vType) {
 			panic("Unsupported function type, must be compatible with BindFunc")
 		}
 	}
@@ -76,6 +88,9 @@ func (o Object) New(v interface{}, k ...Kind) ObjectInterface {
 
 func (o *Object) Singleton() ObjectInterface {
 	o.singleton = true
+	if o.shared == nil {
+		o.shared = new(sharedInstance)
+	}
 	return o
 }
 
@@ -83,6 +98,47 @@ func (o *Object) IsSingleton() bool {
 	return o.singleton
 }
 
+// instance returns the singleton value, calling build to construct it on
+// first use. Concurrent callers wait for the first build rather than running
+// their own; a build that panics is retried by the next caller.
+func (o *Object) instance(build func() interface{}) interface{} {
+	if o.shared == nil {
+		return o.Value
+	}
+	o.shared.mu.Lock()
+	defer o.shared.mu.Unlock()
+	if !o.shared.built {
+		o.shared.value = build()
+		o.shared.built = true
+	}
+	return o.shared.value
+}
+
+// current returns the singleton value without building it: the lazily built
+// instance if there is one, otherwise Value.
+func (o *Object) current() interface{} {
+	if o.shared != nil {
+		o.shared.mu.Lock()
+		defer o.shared.mu.Unlock()
+		if o.shared.built {
+			return o.shared.value
+		}
+	}
+	return o.Value
+}
+
+// forRequester returns the singleton Object holding the instance of a
+// GiveSingleton rule for the requesting type key, creating it on first use.
+func (o *Object) forRequester(key string) *Object {
+	if x, ok := o.perType.Load(key); ok {
+		return x.(*Object)
+	}
+	x := &Object{Value: o.Value, Name: o.Name, Kind: o.Kind, build: o.build}
+	x.Singleton()
+	actual, _ := o.perType.LoadOrStore(key, x)
+	return actual.(*Object)
+}
+
 func (o *Object) String() string {
 	return o.Name
 }
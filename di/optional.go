@@ -0,0 +1,136 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Optional wraps a dependency that may not be bound. As a New() parameter or
+// an inject-tagged field it receives the resolved value with Present set when
+// T has a binding (or a When/Needs/Give rule applies), and the zero Optional
+// otherwise.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Get returns the wrapped value and whether it was resolved.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+func (o Optional[T]) optionalElem() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o Optional[T]) withValue(v interface{}) interface{} {
+	return Optional[T]{Value: v.(T), Present: true}
+}
+
+// optional is implemented by every Optional[T].
+type optional interface {
+	optionalElem() reflect.Type
+	withValue(interface{}) interface{}
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+func isOptionalType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(optionalType)
+}
+
+// makeOptional builds the Optional of type t, resolving its element only when
+// a contextual rule in hintmap or a binding exists for it.
+func (A *App) makeOptional(t reflect.Type, hintmap map[string]ObjectInterface) interface{} {
+	o := reflect.Zero(t).Interface().(optional)
+	elem := o.optionalElem()
+	key := bindingKey(elem)
+
+	if po, ok := hintmap[key]; ok {
+		return o.withValue(A.processObject(po.(*Object), make(map[string]interface{})))
+	}
+	if !A.isBound(elem) {
+		return o
+	}
+	return o.withValue(A.makeType(elem))
+}
+
+// injectOptions are the options of an inject tag.
+type injectOptions struct {
+	optional bool
+}
+
+// parseInjectTag splits an inject tag into its literal value and options.
+// Options are only recognised after a leading comma, e.g. inject:",optional",
+// so literals such as inject:"80,443" are unaffected.
+func parseInjectTag(tag string) (string, injectOptions) {
+	var opts injectOptions
+	if !strings.HasPrefix(tag, ",") {
+		return tag, opts
+	}
+
+	for _, opt := range strings.Split(tag[1:], ",") {
+		switch strings.TrimSpace(opt) {
+		case "optional":
+			opts.optional = true
+		default:
+			panic(fmt.Sprintf("unknown inject tag option %q in %q", opt, tag))
+		}
+	}
+
+	return "", opts
+}
+
+// bindingKey returns the registry key a dependency of type t is resolved
+// under.
+func bindingKey(t reflect.Type) string {
+	if t.Kind() == reflect.Interface {
+		return typeFullName(reflect.PtrTo(t))
+	}
+	return typeFullName(t)
+}
+
+// hasBinding reports whether key resolves through something registered,
+// rather than by auto-generation.
+func (A *App) hasBinding(key string) bool {
+	if _, w := A.withinHint(key); w {
+		return true
+	}
+	if _, e := A.lookupIn(key, registryOf); e {
+		return true
+	}
+	if _, d := A.findDeferred(key); d != nil {
+		return true
+	}
+	if _, e := A.lookupIn(key, defaultsOf); e {
+		return true
+	}
+	return key == contextKey || (key == configSourceKey && A.configSource() != nil)
+}
+
+// isBound is hasBinding for a dependency of type t. With AutoBind, an
+// interface also counts as bound when a catalogued type implements it.
+func (A *App) isBound(t reflect.Type) bool {
+	if A.hasBinding(bindingKey(t)) {
+		return true
+	}
+	if t.Kind() == reflect.Interface {
+		_, ok := A.discover(t)
+		return ok
+	}
+	return false
+}
+
+// makeType resolves a dependency of type t.
+func (A *App) makeType(t reflect.Type) interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return A.makeInternal(reflect.New(t.Elem()).Interface())
+	case reflect.Struct:
+		return A.makeInternal(reflect.New(t).Elem().Interface())
+	case reflect.Interface:
+		return A.makeInternal(reflect.New(t).Interface())
+	}
+	panic(fmt.Sprintf("Could not inject %s (%s)", t, t.Kind()))
+}
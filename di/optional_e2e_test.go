@@ -0,0 +1,136 @@
+package di
+
+import (
+	"testing"
+)
+
+type OptionalTracer interface {
+	Trace(string)
+}
+
+type OptionalStdoutTracer struct{}
+
+func (o *OptionalStdoutTracer) Trace(string) {}
+
+type OptionalMetrics struct {
+	Prefix string `inject:"app"`
+}
+
+type OptionalService struct {
+	Tracer  OptionalTracer           `inject:",optional"`
+	Metrics *OptionalMetrics         `inject:",optional"`
+	Wrapped Optional[OptionalTracer] `inject:""`
+}
+
+type OptionalServiceWithNew struct {
+	Tracer   OptionalTracer
+	HasTrace bool
+	Metrics  *OptionalMetrics `inject:",optional"`
+}
+
+func (o OptionalServiceWithNew) New(t Optional[OptionalTracer]) *OptionalServiceWithNew {
+	tracer, ok := t.Get()
+	return &OptionalServiceWithNew{Tracer: tracer, HasTrace: ok, Metrics: &OptionalMetrics{Prefix: "new"}}
+}
+
+type OptionalBadOption struct {
+	Tracer OptionalTracer `inject:",sometimes"`
+}
+
+func TestOptional_UnboundLeavesZero(t *testing.T) {
+	svc := New().Make(&OptionalService{}).(*OptionalService)
+
+	if svc.Tracer != nil {
+		t.Error("Unbound optional interface should stay nil")
+	}
+	if svc.Metrics != nil {
+		t.Error("Unbound optional pointer should stay nil rather than be auto-generated")
+	}
+	if _, ok := svc.Wrapped.Get(); ok {
+		t.Error("Unbound Optional field should not be present")
+	}
+}
+
+func TestOptional_BoundIsInjected(t *testing.T) {
+	c := New()
+	c.Bind((*OptionalTracer)(nil), &OptionalStdoutTracer{})
+	c.Singleton(&OptionalMetrics{Prefix: "bound"})
+
+	svc := c.Make(&OptionalService{}).(*OptionalService)
+
+	if svc.Tracer == nil {
+		t.Error("Bound optional interface should be injected")
+	}
+	if svc.Metrics == nil || svc.Metrics.Prefix != "bound" {
+		t.Errorf("Bound optional pointer should be injected, got %+v", svc.Metrics)
+	}
+	if tracer, ok := svc.Wrapped.Get(); !ok || tracer == nil {
+		t.Error("Bound Optional field should be present")
+	}
+}
+
+func TestOptional_NewParameter(t *testing.T) {
+	c := New()
+
+	svc := c.Make(&OptionalServiceWithNew{}).(*OptionalServiceWithNew)
+	if svc.HasTrace || svc.Tracer != nil {
+		t.Error("Unbound Optional parameter should be the zero Optional")
+	}
+	if svc.Metrics == nil || svc.Metrics.Prefix != "new" {
+		t.Error("Unbound optional field should keep the value set by New()")
+	}
+
+	c.Bind((*OptionalTracer)(nil), &OptionalStdoutTracer{})
+	svc = c.Make(&OptionalServiceWithNew{}).(*OptionalServiceWithNew)
+	if !svc.HasTrace || svc.Tracer == nil {
+		t.Error("Bound Optional parameter should be present")
+	}
+}
+
+func TestOptional_WhenRuleCountsAsBinding(t *testing.T) {
+	c := New()
+	c.When(&OptionalServiceWithNew{}).Needs((*OptionalTracer)(nil)).Give(&OptionalStdoutTracer{})
+	c.When(&OptionalService{}).Needs((*OptionalTracer)(nil)).Give(&OptionalStdoutTracer{})
+
+	if !c.Make(&OptionalServiceWithNew{}).(*OptionalServiceWithNew).HasTrace {
+		t.Error("Contextual binding should satisfy an Optional parameter")
+	}
+	svc := c.Make(&OptionalService{}).(*OptionalService)
+	if svc.Tracer == nil {
+		t.Error("Contextual binding should satisfy an optional field")
+	}
+	if _, ok := svc.Wrapped.Get(); !ok {
+		t.Error("Contextual binding should satisfy an Optional field")
+	}
+}
+
+func TestOptional_MakeWithOverrides(t *testing.T) {
+	tracer := &OptionalStdoutTracer{}
+	svc := New().MakeWith(&OptionalService{}, map[string]interface{}{"Tracer": tracer}).(*OptionalService)
+
+	if svc.Tracer != tracer {
+		t.Error("MakeWith value should fill an optional field")
+	}
+}
+
+func TestOptional_UnknownOptionPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for an unknown inject option")
+		}
+	}()
+
+	New().Make(&OptionalBadOption{})
+}
+
+func TestOptional_RequiredStillPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("inject:\"\" without optional should still panic when unbound")
+		}
+	}()
+
+	New().Make(&struct {
+		Tracer OptionalTracer `inject:""`
+	}{})
+}
@@ -0,0 +1,104 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Override registers b for a and returns a function that puts back whatever
+// was registered under a before, or nothing if a was unbound. It is meant
+// for test helpers such as ditest.Override.
+//
+// a is a type or name as for Bind, or a When(...).Needs(...) chain from A
+// to override a contextual rule; chains from other containers and other
+// NeedsBuilders panic. A BindFunc or name b is bound as by Bind; any
+// other b is registered as by Singleton, so the instance given, typically a
+// mock, is exactly what gets resolved. A nil b removes the binding until
+// restored.
+func (A *App) Override(a interface{}, b interface{}) (restore func()) {
+	A.checkFrozen("Override")
+	r, exit := A.enter()
+	defer exit()
+
+	switch n := a.(type) {
+	case *needLink:
+		if n.a.container != A.container {
+			panic("Override() requires a When(...).Needs(...) chain from the same container")
+		}
+		return r.overrideHint(n, b)
+	case NeedsBuilder:
+		panic(fmt.Sprintf("Override() cannot override a %T, only a When(...).Needs(...) chain from an App", a))
+	}
+
+	var label string
+	var o ObjectInterface
+	switch {
+	case b == nil:
+		if reflect.TypeOf(a).Kind() == reflect.String {
+			label = a.(string)
+		} else {
+			label = r.typeFullName(reflect.TypeOf(a))
+		}
+	case reflect.TypeOf(b).Kind() != reflect.Func && reflect.TypeOf(b).Kind() != reflect.String && r.validSingletonCombination(a, b):
+		label, o = r.newSingleton(a, b)
+	default:
+		label, o = r.newBinding(a, b)
+	}
+
+	return A.swap(label, o)
+}
+
+// overrideHint is Override for a contextual rule.
+func (A *App) overrideHint(n *needLink, b interface{}) func() {
+	wKeys, aKey := n.keys()
+
+	A.appMu.RLock()
+	prev := make([]ObjectInterface, len(wKeys))
+	for i, wKey := range wKeys {
+		prev[i] = A.injectRegistry[wKey][aKey]
+	}
+	A.appMu.RUnlock()
+
+	n.Give(b)
+
+	return func() {
+		defer A.writeLock("Override")()
+		for i, wKey := range wKeys {
+			A.injectRegistry[wKey] = copyHints(A.injectRegistry[wKey], aKey, prev[i])
+		}
+	}
+}
+
+// swap binds o under key, removing the binding when o is nil, and returns
+// a function restoring the previous binding.
+func (A *App) swap(key string, o ObjectInterface) func() {
+	unlock := A.writeLock("Override")
+	prev := A.registry[key]
+	setBinding(A.registry, key, o)
+	unlock()
+
+	return func() {
+		defer A.writeLock("Override")()
+		setBinding(A.registry, key, prev)
+	}
+}
+
+func setBinding(registry map[string]ObjectInterface, key string, o ObjectInterface) {
+	if o == nil {
+		delete(registry, key)
+	} else {
+		registry[key] = o
+	}
+}
+
+// Isolate replaces the default and named instances returned by Default with
+// none, so the next Default or New creates a fresh default, and returns a
+// function putting the previous ones back. It is meant for test helpers
+// such as ditest.Isolated.
+func Isolate() (restore func()) {
+	prevDefault, prevInstances := global.swap(nil, nil)
+
+	return func() {
+		global.swap(prevDefault, prevInstances)
+	}
+}
@@ -0,0 +1,108 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Provider resolves a T from the container each time it is called. As an
+// inject-tagged field or a New() parameter it defers construction until
+// first use, lets a singleton obtain fresh transient instances, and breaks
+// cycles that would otherwise be rejected as circular. Plain func() T and
+// func() (T, error) fields are injected the same way; the latter returns
+// resolution failures as an error instead of panicking.
+type Provider[T any] func() T
+
+// isProviderType reports whether t is a func() T or func() (T, error) that
+// can be injected as a provider of a struct, pointer or interface T.
+func isProviderType(t reflect.Type) bool {
+	if t.Kind() != reflect.Func || t.NumIn() != 0 {
+		return false
+	}
+	switch t.NumOut() {
+	case 1:
+	case 2:
+		if t.Out(1) != errorType {
+			return false
+		}
+	default:
+		return false
+	}
+	switch t.Out(0).Kind() {
+	case reflect.Ptr, reflect.Struct, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// makeProvider builds a function of provider type t resolving its result
+// through A on every call. A When/Needs/Give rule for the result type in
+// hintmap, i.e. for the struct the provider is injected into, applies.
+func (A *App) makeProvider(t reflect.Type, hintmap map[string]ObjectInterface) reflect.Value {
+	elem := t.Out(0)
+	po, hinted := hintmap[bindingKey(elem)]
+
+	// Within() rules of the types being resolved still apply when the
+	// provider is called later, and while the resolution it was made in
+	// runs, resolving those types again is still circular
+	var path []reflect.Type
+	var resolving map[string]bool
+	if A.res != nil {
+		path = append(path, A.res.path...)
+		resolving = copyResolving(A.res.resolving)
+	}
+
+	resolve := func() reflect.Value {
+		// Every call gets a resolution of its own, so a constructor may
+		// hand the provider to goroutines before it returns
+		var r *App
+		if A.res != nil && !A.res.done.Load() {
+			r = &App{container: A.container, loading: A.loading, res: &resolution{ctx: A.res.ctx, resolving: copyResolving(resolving)}}
+			defer r.res.done.Store(true)
+		} else {
+			var exit func()
+			r, exit = A.enter()
+			defer exit()
+		}
+		r.res.path = path[:len(path):len(path)]
+
+		var c interface{}
+		if hinted {
+			c = r.processObject(po.(*Object), make(map[string]interface{}))
+		} else {
+			c = r.makeType(elem)
+		}
+		v := reflect.New(elem).Elem()
+		if c != nil {
+			v.Set(reflect.ValueOf(c))
+		}
+		return v
+	}
+
+	if t.NumOut() == 1 {
+		return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{resolve()}
+		})
+	}
+
+	return reflect.MakeFunc(t, func([]reflect.Value) (out []reflect.Value) {
+		defer func() {
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				if !ok {
+					err = fmt.Errorf("%v", r)
+				}
+				out = []reflect.Value{reflect.Zero(elem), reflect.ValueOf(&err).Elem()}
+			}
+		}()
+		return []reflect.Value{resolve(), reflect.Zero(errorType)}
+	})
+}
+
+func copyResolving(resolving map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(resolving))
+	for k, v := range resolving {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,163 @@
+package di
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type ProviderCounter struct {
+	ID int
+}
+
+type ProviderCycleA struct {
+	B *ProviderCycleB `inject:""`
+}
+
+type ProviderCycleB struct {
+	A Provider[*ProviderCycleA] `inject:""`
+}
+
+type ProviderHolder struct {
+	Next    func() *ProviderCounter          `inject:""`
+	TryNext func() (*ProviderCounter, error) `inject:""`
+}
+
+type ProviderGreeter interface {
+	Greet() string
+}
+
+type ProviderEnglish struct{}
+
+func (p *ProviderEnglish) Greet() string { return "hello" }
+
+type ProviderFrench struct{}
+
+func (p *ProviderFrench) Greet() string { return "bonjour" }
+
+type ProviderGreeterHolder struct {
+	Greeter    Provider[ProviderGreeter]       `inject:""`
+	TryGreeter func() (ProviderGreeter, error) `inject:""`
+}
+
+type ProviderCtorHolder struct {
+	greeter Provider[ProviderGreeter]
+}
+
+func (p ProviderCtorHolder) New(g Provider[ProviderGreeter]) *ProviderCtorHolder {
+	return &ProviderCtorHolder{greeter: g}
+}
+
+type ProviderFanOut struct {
+	counters []*ProviderCounter
+}
+
+// New calls its provider from several goroutines before returning.
+func (p ProviderFanOut) New(next Provider[*ProviderCounter]) *ProviderFanOut {
+	counters := make([]*ProviderCounter, 8)
+	var wg sync.WaitGroup
+	for i := range counters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			counters[i] = next()
+		}(i)
+	}
+	wg.Wait()
+	return &ProviderFanOut{counters: counters}
+}
+
+func TestProvider_BreaksCycle(t *testing.T) {
+	a := New().Make(&ProviderCycleA{}).(*ProviderCycleA)
+
+	other := a.B.A()
+	if other == nil || other == a || other.B == nil {
+		t.Error("Expected provider to resolve a new ProviderCycleA on demand")
+	}
+}
+
+func TestProvider_FreshInstancesAndDeferredConstruction(t *testing.T) {
+	c := New()
+	built := 0
+	c.Bind(&ProviderCounter{}, func(a *App) interface{} {
+		built++
+		return &ProviderCounter{ID: built}
+	})
+
+	h := c.Make(&ProviderHolder{}).(*ProviderHolder)
+	if built != 0 {
+		t.Fatal("Expected nothing to be built before the provider is called")
+	}
+
+	first, second := h.Next(), h.Next()
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("Expected fresh instances 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	third, err := h.TryNext()
+	if err != nil || third.ID != 3 {
+		t.Errorf("Expected instance 3 without error, got %v, %v", third, err)
+	}
+}
+
+func TestProvider_ErrorForm(t *testing.T) {
+	h := New().Make(&ProviderGreeterHolder{}).(*ProviderGreeterHolder)
+
+	g, err := h.TryGreeter()
+	if err == nil || g != nil {
+		t.Fatal("Expected an error resolving an unbound interface")
+	}
+	if !strings.Contains(err.Error(), "no binding found") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected Provider[T] to panic on failure")
+		}
+	}()
+	h.Greeter()
+}
+
+func TestProvider_ErrorFormReturnsPanickedError(t *testing.T) {
+	c := New()
+	c.Bind((*ProviderGreeter)(nil), func(a *App) interface{} {
+		panic(errors.New("boom"))
+	})
+
+	h := c.Make(&ProviderGreeterHolder{}).(*ProviderGreeterHolder)
+	if _, err := h.TryGreeter(); err == nil || err.Error() != "boom" {
+		t.Errorf("Expected the panicked error to be returned, got %v", err)
+	}
+}
+
+func TestProvider_WhenRuleOfRequester(t *testing.T) {
+	c := New()
+	c.Bind((*ProviderGreeter)(nil), &ProviderEnglish{})
+	c.When(&ProviderGreeterHolder{}).Needs((*ProviderGreeter)(nil)).Give(&ProviderFrench{})
+
+	h := c.Make(&ProviderGreeterHolder{}).(*ProviderGreeterHolder)
+	if h.Greeter().Greet() != "bonjour" {
+		t.Error("Expected the requester's When rule to apply to the provider")
+	}
+}
+
+func TestProvider_NewParameter(t *testing.T) {
+	c := New()
+	c.Bind((*ProviderGreeter)(nil), &ProviderEnglish{})
+
+	h := c.Make(&ProviderCtorHolder{}).(*ProviderCtorHolder)
+	if h.greeter().Greet() != "hello" {
+		t.Error("Expected provider New() parameter to resolve the binding")
+	}
+}
+
+func TestProvider_ConcurrentCallsDuringConstruction(t *testing.T) {
+	f := New().Make(&ProviderFanOut{}).(*ProviderFanOut)
+	for _, c := range f.counters {
+		if c == nil {
+			t.Fatal("Expected every goroutine to resolve a ProviderCounter")
+		}
+	}
+}
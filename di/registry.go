@@ -0,0 +1,229 @@
+package di
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Registry holds a default container and named containers. The package
+// functions Default and New, and AppConfig.Name and AppConfig.Default, work
+// on GlobalRegistry(); a Registry from NewRegistry lets a process or a test
+// manage its containers without touching the global one.
+type Registry struct {
+	mu         sync.RWMutex
+	defaultApp AppInterface
+	instances  map[string]AppInterface
+	adopt      bool // the first container created becomes the default
+}
+
+// global is the registry behind Default and New. It keeps their original
+// behaviour of making the first container created the default.
+var global = &Registry{adopt: true}
+
+// GlobalRegistry returns the registry used by Default and New.
+func GlobalRegistry() *Registry {
+	return global
+}
+
+// NewRegistry returns an empty Registry. Unlike the global registry it has
+// no default until one is set, created by Default, or created by New with
+// AppConfig.Default.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// New creates a container, registering it under AppConfig.Name and as the
+// default when AppConfig.Default is set.
+func (r *Registry) New(config ...AppConfig) *App {
+	a := newAppInstance()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.adopt && r.defaultApp == nil {
+		r.defaultApp = a
+	}
+
+	// Process config options - allows providing mocked objectBuilder & typeChecker for example
+	if len(config) > 0 {
+		c := config[0]
+		if len(c.Name) > 0 {
+			if r.instances == nil {
+				r.instances = make(map[string]AppInterface)
+			}
+
+			r.instances[c.Name] = a
+		}
+		if c.Default {
+			r.defaultApp = a
+		}
+		if c.ObjectBuilder != nil {
+			a.objectBuilder = c.ObjectBuilder
+		}
+		if c.TypeChecker != nil {
+			a.typeChecker = c.TypeChecker
+		}
+		if c.Env != nil {
+			a.env = c.Env
+		}
+		a.unexported = c.InjectUnexported
+		a.autoBind = c.AutoBind
+	}
+
+	return a
+}
+
+// Default returns the default or a named container, creating one if it
+// doesn't exist.
+func (r *Registry) Default(name ...string) AppInterface {
+	if len(name) > 0 && len(name[0]) > 0 {
+		r.mu.RLock()
+		m := r.instances[name[0]]
+		r.mu.RUnlock()
+		if m == nil {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.instances[name[0]] != nil {
+				return r.instances[name[0]]
+			}
+			if r.instances == nil {
+				r.instances = make(map[string]AppInterface)
+			}
+			r.instances[name[0]] = newAppInstance()
+			return r.instances[name[0]]
+		}
+
+		return m
+	}
+
+	r.mu.RLock()
+	if r.defaultApp != nil {
+		a := r.defaultApp
+		r.mu.RUnlock()
+		return a
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.defaultApp != nil {
+		return r.defaultApp
+	}
+	r.defaultApp = newAppInstance()
+	return r.defaultApp
+}
+
+// Get returns the named container, or the default for an empty name,
+// without creating it.
+func (r *Registry) Get(name string) (AppInterface, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		return r.defaultApp, r.defaultApp != nil
+	}
+	a, ok := r.instances[name]
+	return a, ok
+}
+
+// Set registers app under name, replacing any container registered there.
+// An empty name sets the default.
+func (r *Registry) Set(name string, app AppInterface) {
+	if app == nil {
+		panic("Set() requires a non-nil container, use Remove to unregister one")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		r.defaultApp = app
+		return
+	}
+	if r.instances == nil {
+		r.instances = make(map[string]AppInterface)
+	}
+	r.instances[name] = app
+}
+
+// SetDefault makes app the default container.
+func (r *Registry) SetDefault(app AppInterface) {
+	r.Set("", app)
+}
+
+// List returns the names of the registered containers, sorted.
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sortedNames(r.instances)
+}
+
+// Remove unregisters the named container, or the default for an empty
+// name, and reports whether there was one. The container is not disposed.
+func (r *Registry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		had := r.defaultApp != nil
+		r.defaultApp = nil
+		return had
+	}
+	_, had := r.instances[name]
+	delete(r.instances, name)
+	return had
+}
+
+// Reset unregisters every container, including the default, without
+// disposing them.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultApp = nil
+	r.instances = nil
+}
+
+// Close unregisters every container and disposes each of them once, even
+// when registered under several names. Errors from Dispose are joined and
+// returned.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	apps := make([]AppInterface, 0, len(r.instances)+1)
+	if r.defaultApp != nil {
+		apps = append(apps, r.defaultApp)
+	}
+	for _, name := range sortedNames(r.instances) {
+		apps = append(apps, r.instances[name])
+	}
+	r.defaultApp = nil
+	r.instances = nil
+	r.mu.Unlock()
+
+	var errs []error
+	disposed := make(map[AppInterface]bool, len(apps))
+	for _, a := range apps {
+		if disposed[a] {
+			continue
+		}
+		disposed[a] = true
+		if err := a.Dispose(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// swap replaces the registry's containers, returning the previous ones.
+func (r *Registry) swap(defaultApp AppInterface, instances map[string]AppInterface) (AppInterface, map[string]AppInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prevDefault, prevInstances := r.defaultApp, r.instances
+	r.defaultApp, r.instances = defaultApp, instances
+	return prevDefault, prevInstances
+}
+
+func sortedNames(instances map[string]AppInterface) []string {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,124 @@
+package di
+
+import (
+	"reflect"
+	"testing"
+)
+
+type RegistryService struct {
+	Name string
+}
+
+func TestRegistry_NewRegistersNamedAndDefault(t *testing.T) {
+	r := NewRegistry()
+	a := r.New(AppConfig{Name: "billing"})
+	b := r.New(AppConfig{Name: "users", Default: true})
+
+	if got, ok := r.Get("billing"); !ok || got != a {
+		t.Error("expected billing container to be registered")
+	}
+	if got, ok := r.Get(""); !ok || got != b {
+		t.Error("expected users container to be the default")
+	}
+	if r.Default("users") != b {
+		t.Error("expected Default(name) to return the registered container")
+	}
+}
+
+func TestRegistry_NoImplicitDefault(t *testing.T) {
+	r := NewRegistry()
+	r.New(AppConfig{Name: "billing"})
+
+	if _, ok := r.Get(""); ok {
+		t.Error("expected no default before one is set")
+	}
+	d := r.Default()
+	if d == nil || d != r.Default() {
+		t.Error("expected Default() to create the default once")
+	}
+}
+
+func TestRegistry_SetListRemove(t *testing.T) {
+	r := NewRegistry()
+	a, b := New(), New()
+	r.Set("b", b)
+	r.Set("a", a)
+	r.SetDefault(a)
+
+	if got := r.List(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected sorted names, got %v", got)
+	}
+	if !r.Remove("b") || r.Remove("b") {
+		t.Error("expected Remove to report whether the name was registered")
+	}
+	if !r.Remove("") {
+		t.Error("expected Remove(\"\") to remove the default")
+	}
+	if _, ok := r.Get(""); ok {
+		t.Error("expected default to be removed")
+	}
+
+	r.Reset()
+	if len(r.List()) != 0 {
+		t.Error("expected Reset to remove all containers")
+	}
+}
+
+func TestRegistry_SetNilPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for nil container")
+		}
+	}()
+	NewRegistry().Set("a", nil)
+}
+
+func TestRegistry_CloseDisposesEachOnce(t *testing.T) {
+	r := NewRegistry()
+	a := r.New(AppConfig{Name: "a", Default: true})
+	r.Set("alias", a)
+	b := r.New(AppConfig{Name: "b"})
+	a.Bind("svc", &RegistryService{Name: "a"})
+	b.Bind("svc", &RegistryService{Name: "b"})
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.List()) != 0 {
+		t.Error("expected Close to unregister all containers")
+	}
+	if _, ok := r.Get(""); ok {
+		t.Error("expected Close to unregister the default")
+	}
+	if a.hasBinding("svc") || b.hasBinding("svc") {
+		t.Error("expected Close to dispose the containers")
+	}
+}
+
+func TestRegistry_CloseReturnsDisposeErrors(t *testing.T) {
+	r := NewRegistry()
+	a := r.New(AppConfig{Name: "a"})
+	a.Freeze()
+
+	if err := r.Close(); err == nil {
+		t.Error("expected Close to return the Dispose error of a frozen container")
+	}
+}
+
+func TestGlobalRegistry_BacksDefault(t *testing.T) {
+	defer Isolate()()
+
+	a := New(AppConfig{Name: "registry-global"})
+	if got, ok := GlobalRegistry().Get("registry-global"); !ok || got != a {
+		t.Error("expected New to register in the global registry")
+	}
+	if Default() != a {
+		t.Error("expected the first container to become the global default")
+	}
+
+	b := New()
+	GlobalRegistry().SetDefault(b)
+	if Default() != b {
+		t.Error("expected SetDefault to change the global default")
+	}
+}
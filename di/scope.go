@@ -0,0 +1,53 @@
+package di
+
+import (
+	"errors"
+	"io"
+)
+
+// Scope returns a child container layered over A. Lookups that miss in the
+// scope fall through to A, so BindFuncs registered on A run against the scope
+// and can resolve anything bound there. Bind and Singleton on the scope leave
+// A untouched. Scopes are never registered as default or named instances.
+func (A *App) Scope() AppInterface {
+	s := newAppInstance()
+	s.parent = A.self
+	s.objectBuilder = A.objectBuilder
+	s.typeChecker = A.typeChecker
+	s.env = A.env
+	s.unexported = A.unexported
+	s.autoBind = A.autoBind
+	return s
+}
+
+// Dispose drops every binding registered directly on A, closing singleton
+// values that implement io.Closer. Bindings inherited from a parent are left
+// alone. Errors from Close are joined and returned.
+func (A *App) Dispose() error {
+	A.appMu.Lock()
+	if A.frozen.Load() {
+		A.appMu.Unlock()
+		return &FrozenContainerError{Op: "Dispose"}
+	}
+	registry := A.registry
+	A.registry = make(map[string]ObjectInterface)
+	A.defaults = make(map[string]ObjectInterface)
+	A.injectRegistry = make(map[string]map[string]ObjectInterface)
+	A.requesters = nil
+	A.appMu.Unlock()
+
+	var errs []error
+	for _, o := range registry {
+		x, ok := o.(*Object)
+		if !ok || !x.IsSingleton() {
+			continue
+		}
+		if c, ok := x.current().(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
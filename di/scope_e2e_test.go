@@ -0,0 +1,99 @@
+package di
+
+import (
+	"errors"
+	"testing"
+)
+
+type ScopeClosable struct {
+	Closed bool
+	Err    error
+}
+
+func (s *ScopeClosable) Close() error {
+	s.Closed = true
+	return s.Err
+}
+
+type ScopeRequestInfo struct {
+	Path string
+}
+
+type ScopeConsumer struct {
+	Info      *ScopeRequestInfo `inject:""`
+	Container *App              `di:""`
+}
+
+func TestScope_InheritsParentBindings(t *testing.T) {
+	c := New()
+	c.Bind((*UserService)(nil), &MockUserService{Name: "parent"})
+
+	s := c.Scope()
+
+	if s.Make((*UserService)(nil)).(UserService).GetUserName() != "parent" {
+		t.Error("Scope should resolve bindings registered on its parent")
+	}
+}
+
+func TestScope_BindingsDoNotLeakToParent(t *testing.T) {
+	c := New()
+	c.Bind((*UserService)(nil), &MockUserService{Name: "parent"})
+
+	s := c.Scope()
+	s.Bind((*UserService)(nil), &MockUserService{Name: "scope"})
+
+	if s.Make((*UserService)(nil)).(UserService).GetUserName() != "scope" {
+		t.Error("Scope binding should shadow the parent's")
+	}
+	if c.Make((*UserService)(nil)).(UserService).GetUserName() != "parent" {
+		t.Error("Scope binding should not affect the parent")
+	}
+}
+
+func TestScope_ParentBindFuncResolvesAgainstScope(t *testing.T) {
+	c := New()
+	c.Bind((*UserService)(nil), func(a *App) interface{} {
+		info := a.Make(&ScopeRequestInfo{}).(*ScopeRequestInfo)
+		return &MockUserService{Name: info.Path}
+	})
+
+	s := c.Scope()
+	s.Singleton(&ScopeRequestInfo{Path: "/users"})
+
+	if s.Make((*UserService)(nil)).(UserService).GetUserName() != "/users" {
+		t.Error("Parent BindFunc should see singletons bound in the scope")
+	}
+}
+
+func TestScope_DiTagInjectsScope(t *testing.T) {
+	c := New()
+	s := c.Scope()
+
+	consumer := s.Make(&ScopeConsumer{}).(*ScopeConsumer)
+	if consumer.Container != s {
+		t.Error("di tag should inject the scope that resolved the struct")
+	}
+}
+
+func TestScope_DisposeClosesSingletons(t *testing.T) {
+	c := New()
+	parent := &ScopeClosable{}
+	c.Singleton(parent)
+
+	s := c.Scope()
+	own := &ScopeClosable{Err: errors.New("close failed")}
+	s.Singleton(own)
+
+	if err := s.Dispose(); err == nil || err.Error() != "close failed" {
+		t.Errorf("Expected Dispose to return the Close error, got %v", err)
+	}
+	if !own.Closed {
+		t.Error("Dispose should close singletons bound in the scope")
+	}
+	if parent.Closed {
+		t.Error("Dispose should not close singletons inherited from the parent")
+	}
+	if s.Make(&ScopeClosable{}) != parent {
+		t.Error("Disposed scope should fall back to the parent's bindings")
+	}
+}
@@ -0,0 +1,71 @@
+package di
+
+import (
+	"fmt"
+)
+
+// ServiceProvider packages the bindings of a module. Register only binds
+// things; Boot may resolve them, since by the time it runs every provider
+// has been registered.
+type ServiceProvider interface {
+	Register(*App)
+	Boot(*App) error
+}
+
+// RegisterProvider adds a provider to be registered and booted by the next
+// call to Boot. A DeferredServiceProvider is instead loaded the first time
+// one of the keys it provides is resolved.
+func (A *App) RegisterProvider(p ServiceProvider) AppInterface {
+	A.checkFrozen("RegisterProvider")
+	if p == nil {
+		panic("RegisterProvider() requires a non-nil ServiceProvider")
+	}
+	if d, ok := p.(DeferredServiceProvider); ok {
+		A.deferProvider(d)
+		return A
+	}
+
+	unlock := A.writeLock("RegisterProvider")
+	A.providers = append(A.providers, p)
+	unlock()
+
+	return A
+}
+
+// Boot runs Register on every provider not yet registered, in the order
+// they were added, and then Boot on each of them. Every provider is
+// registered and booted exactly once, so Boot may be called again to take
+// in providers added since. Providers added from within Register or Boot
+// are registered before any further provider boots; providers must not call
+// Boot themselves. The first Boot error stops the process and is returned;
+// providers after it stay pending.
+func (A *App) Boot() error {
+	A.bootMu.Lock()
+	defer A.bootMu.Unlock()
+
+	for {
+		for p := A.provider(A.registered); p != nil; p = A.provider(A.registered) {
+			A.registered++
+			p.Register(A.self)
+		}
+
+		p := A.provider(A.booted)
+		if p == nil {
+			return nil
+		}
+		A.booted++
+		if err := p.Boot(A.self); err != nil {
+			return fmt.Errorf("booting %T: %w", p, err)
+		}
+	}
+}
+
+// provider returns the i-th provider added, or nil.
+func (A *App) provider(i int) ServiceProvider {
+	A.appMu.RLock()
+	defer A.appMu.RUnlock()
+	if i < len(A.providers) {
+		return A.providers[i]
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package di
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ProviderLog struct {
+	Entries []string
+}
+
+type ProviderDatabase struct {
+	DSN string
+}
+
+type ProviderDatabaseModule struct {
+	log *ProviderLog
+}
+
+func (p *ProviderDatabaseModule) Register(a *App) {
+	p.log.Entries = append(p.log.Entries, "register db")
+	a.Singleton(&ProviderDatabase{DSN: "postgres://"})
+}
+
+func (p *ProviderDatabaseModule) Boot(a *App) error {
+	p.log.Entries = append(p.log.Entries, "boot db")
+	return nil
+}
+
+type ProviderRepository struct {
+	DB *ProviderDatabase `inject:""`
+}
+
+type ProviderRepoModule struct {
+	log  *ProviderLog
+	repo *ProviderRepository
+}
+
+func (p *ProviderRepoModule) Register(a *App) {
+	p.log.Entries = append(p.log.Entries, "register repo")
+}
+
+func (p *ProviderRepoModule) Boot(a *App) error {
+	p.log.Entries = append(p.log.Entries, "boot repo")
+	p.repo = a.Make(&ProviderRepository{}).(*ProviderRepository)
+	return nil
+}
+
+type ProviderFailingModule struct {
+	log *ProviderLog
+}
+
+func (p *ProviderFailingModule) Register(a *App) {
+	p.log.Entries = append(p.log.Entries, "register failing")
+}
+
+func (p *ProviderFailingModule) Boot(a *App) error {
+	p.log.Entries = append(p.log.Entries, "boot failing")
+	return errProviderBoot
+}
+
+var errProviderBoot = errors.New("boot failed")
+
+func TestBoot_RegistersAllBeforeBootingInOrder(t *testing.T) {
+	c := New()
+	log := &ProviderLog{}
+	repo := &ProviderRepoModule{log: log}
+	// The repo module boots before the database module is booted, but
+	// after it has been registered
+	c.RegisterProvider(repo)
+	c.RegisterProvider(&ProviderDatabaseModule{log: log})
+
+	if len(log.Entries) != 0 {
+		t.Fatal("RegisterProvider should not run anything before Boot")
+	}
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"register repo", "register db", "boot repo", "boot db"}
+	if !reflect.DeepEqual(log.Entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, log.Entries)
+	}
+	if repo.repo.DB.DSN != "postgres://" {
+		t.Error("Expected Boot to resolve bindings made by another provider")
+	}
+}
+
+func TestBoot_RunsEachProviderOnce(t *testing.T) {
+	c := New()
+	log := &ProviderLog{}
+	c.RegisterProvider(&ProviderDatabaseModule{log: log})
+
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+	c.RegisterProvider(&ProviderRepoModule{log: log})
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"register db", "boot db", "register repo", "boot repo"}
+	if !reflect.DeepEqual(log.Entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, log.Entries)
+	}
+}
+
+func TestBoot_ReturnsFirstError(t *testing.T) {
+	c := New()
+	log := &ProviderLog{}
+	c.RegisterProvider(&ProviderFailingModule{log: log})
+	c.RegisterProvider(&ProviderDatabaseModule{log: log})
+
+	err := c.Boot()
+	if !errors.Is(err, errProviderBoot) {
+		t.Fatalf("Expected wrapped boot error, got %v", err)
+	}
+
+	expected := []string{"register failing", "register db", "boot failing"}
+	if !reflect.DeepEqual(log.Entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, log.Entries)
+	}
+
+	if err := c.Boot(); err != nil {
+		t.Fatal(err)
+	}
+	if log.Entries[len(log.Entries)-1] != "boot db" {
+		t.Error("Expected the remaining provider to boot on the next call")
+	}
+}
@@ -0,0 +1,21 @@
+package di
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// settableField returns field in a form that can be Set, and whether it can
+// be. Exported fields are returned as is. Unexported fields of an addressable
+// struct are only made settable when the container was created with
+// AppConfig.InjectUnexported; the value returned then aliases the same memory
+// without the read-only flag reflect puts on unexported fields.
+func (A *App) settableField(field reflect.Value) (reflect.Value, bool) {
+	if field.CanSet() {
+		return field, true
+	}
+	if !A.unexported || !field.CanAddr() {
+		return field, false
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem(), true
+}
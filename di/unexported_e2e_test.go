@@ -0,0 +1,88 @@
+package di
+
+import (
+	"testing"
+)
+
+type UnexportedDep struct {
+	Name string `inject:"dep"`
+}
+
+type UnexportedHolder struct {
+	dep   *UnexportedDep `inject:""`
+	label string         `inject:"hello"`
+	app   *App           `di:""`
+}
+
+type UnexportedCtorHolder struct {
+	dep *UnexportedDep `di:""`
+}
+
+func (u UnexportedCtorHolder) New() *UnexportedCtorHolder {
+	return &UnexportedCtorHolder{}
+}
+
+func TestInjectUnexported_Disabled(t *testing.T) {
+	c := New()
+
+	h := c.Make(&UnexportedHolder{}).(*UnexportedHolder)
+	if h.dep != nil || h.label != "" || h.app != nil {
+		t.Error("Unexported fields should be skipped unless InjectUnexported is set")
+	}
+}
+
+func TestInjectUnexported_Enabled(t *testing.T) {
+	c := New(AppConfig{InjectUnexported: true})
+
+	h := c.Make(&UnexportedHolder{}).(*UnexportedHolder)
+	if h.dep == nil || h.dep.Name != "dep" {
+		t.Error("Expected unexported pointer field to be injected")
+	}
+	if h.label != "hello" {
+		t.Errorf("Expected hello, got %q", h.label)
+	}
+	if h.app != c {
+		t.Error("Expected di tag to inject the container into an unexported field")
+	}
+}
+
+func TestInjectUnexported_AfterNew(t *testing.T) {
+	c := New(AppConfig{InjectUnexported: true})
+
+	h := c.Make(&UnexportedCtorHolder{}).(*UnexportedCtorHolder)
+	if h.dep == nil {
+		t.Error("Expected unexported field to be injected after New()")
+	}
+}
+
+func TestInjectUnexported_InheritedByScope(t *testing.T) {
+	c := New(AppConfig{InjectUnexported: true})
+
+	h := c.Scope().Make(UnexportedHolder{}).(UnexportedHolder)
+	if h.dep == nil || h.label != "hello" {
+		t.Error("Scopes should inherit InjectUnexported")
+	}
+}
+
+type UnexportedDBConfig struct {
+	Host  string
+	cache map[string]string
+	port  int `config:"port"`
+}
+
+type UnexportedConfigHolder struct {
+	DB UnexportedDBConfig `config:"db"`
+}
+
+func TestInjectUnexported_ConfigNestedNeedsTag(t *testing.T) {
+	c := New(AppConfig{InjectUnexported: true})
+	c.BindConfig(ConfigMap{"db": map[string]interface{}{"host": "localhost", "port": 5432}})
+
+	h := c.Make(&UnexportedConfigHolder{}).(*UnexportedConfigHolder)
+	if h.DB.Host != "localhost" || h.DB.port != 5432 {
+		t.Errorf("Expected localhost:5432, got %s:%d", h.DB.Host, h.DB.port)
+	}
+	if h.DB.cache != nil {
+		t.Error("Expected untagged unexported field to be skipped")
+	}
+}
@@ -2,18 +2,51 @@ package di
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
+	"sync"
 )
 
+// WhenBuilder is the first step of a contextual binding chain, returned by
+// When.
+type WhenBuilder interface {
+	Needs(interface{}) NeedsBuilder
+	Within() WhenBuilder
+}
+
+// NeedsBuilder is the second step of a contextual binding chain, returned by
+// Needs. Give completes it.
+type NeedsBuilder interface {
+	Give(interface{}) ObjectInterface
+	GiveSingleton(interface{}, ...GiveConfig) ObjectInterface
+	GiveFactory(interface{}) ObjectInterface
+}
+
+// GiveConfig provides options for NeedsBuilder.GiveSingleton.
+type GiveConfig struct {
+	// Global builds one instance shared by every requesting type, instead of
+	// one per requesting type.
+	Global bool
+}
+
 // whenLink is the first step of a contextual binding chain:
 // app.When(&RequestingType{}).Needs((*Dependency)(nil)).Give(&Impl{})
 type whenLink struct {
-	a    *App
-	when interface{}
+	a      *App
+	whens  []interface{}
+	within bool
 }
 
-// Needs specifies which dependency type to override for the requesting type.
-func (w *whenLink) Needs(a interface{}) *needLink {
+// Within makes the rule transitive: it applies to the whole dependency
+// subgraph of the requesting types, not just their own fields and New()
+// parameters.
+func (w *whenLink) Within() WhenBuilder {
+	return &whenLink{w.a, w.whens, true}
+}
+
+// Needs specifies which dependency type to override for the requesting types.
+func (w *whenLink) Needs(a interface{}) NeedsBuilder {
 	return &needLink{
 		w.a,
 		w,
@@ -28,45 +61,446 @@ type needLink struct {
 	need interface{}
 }
 
-// Give completes the contextual binding: when the requesting type needs the
-// dependency, give it b instead of the default binding. Pass nil to remove.
+// Give completes the contextual binding: when any of the requesting types
+// needs the dependency, give it b instead of the default binding. Pass nil
+// to remove.
 func (n *needLink) Give(b interface{}) ObjectInterface {
-	A := n.a
-	A.lock()
-	defer A.unlock()
+	n.a.checkFrozen("Give")
+	A, exit := n.a.enter()
+	defer exit()
+
+	wKeys, aKey := n.keys()
+
+	if b == nil {
+		defer A.writeLock("Give")()
+		var object ObjectInterface
+		for _, wKey := range wKeys {
+			o := A.injectRegistry[wKey][aKey]
+			if o == nil {
+				continue
+			}
+			if object == nil {
+				object = o
+			}
+			A.injectRegistry[wKey] = copyHints(A.injectRegistry[wKey], aKey, nil)
+		}
+		return object
+	}
 
-	w := n.when.when
+	var object ObjectInterface
+	if isNamedHint(aKey) {
+		// Any value for a named field or parameter, given as is unless it
+		// is a BindFunc
+		if reflect.TypeOf(b).Kind() == reflect.Func {
+			object = A.objectBuilder.New(b)
+		} else {
+			object = A.objectBuilder.New(b, Primitive)
+		}
+	} else {
+		if a := n.need; !A.validBindCombination(a, b) && !A.validSingletonCombination(a, b) {
+			panic(fmt.Sprintf("Can not assign %s to %s for %s", reflect.TypeOf(b), reflect.TypeOf(a), n.when))
+		}
+		object = A.objectBuilder.New(b)
+	}
+
+	A.setHints("Give", n.when, wKeys, aKey, object)
+
+	return object
+}
+
+// GiveSingleton completes the contextual binding like Give, but b is built
+// once per requesting type, or once for all of them with GiveConfig.Global,
+// on first use. b is validated as for Singleton: a BindFunc, a nil pointer
+// to build by auto-generation, or an instance, which is always shared.
+// Pass nil to remove.
+func (n *needLink) GiveSingleton(b interface{}, config ...GiveConfig) ObjectInterface {
+	if b == nil {
+		return n.Give(nil)
+	}
+	n.a.checkFrozen("GiveSingleton")
+	A, exit := n.a.enter()
+	defer exit()
+
+	wKeys, aKey := n.keys()
+
+	if !A.validSingletonCombination(n.need, b) {
+		panic(fmt.Sprintf("Unsupported input, cannot bind singleton %s to %s for %s", reflect.TypeOf(b), reflect.TypeOf(n.need), n.when))
+	}
+
+	object := A.objectBuilder.New(b)
+	if x, ok := object.(*Object); ok {
+		if bv := reflect.ValueOf(b); bv.Kind() == reflect.Ptr && bv.IsNil() {
+			x.build = func(r *App) interface{} {
+				return r.autogen(b, make(map[string]interface{}))
+			}
+		}
+		if len(config) > 0 && config[0].Global {
+			x.Singleton()
+			x.recreate = x.build
+		} else {
+			x.perType = new(sync.Map)
+		}
+	}
+
+	A.setHints("GiveSingleton", n.when, wKeys, aKey, object)
+
+	return object
+}
+
+// GiveFactory completes the contextual binding with a function called on
+// every injection: a BindFunc, ContextBindFunc or InterfaceBindFunc, or a
+// constructor such as func(*Config, Logger) *Client whose parameters are
+// resolved from the container and which may also return an error. f must
+// return the needed type. Pass nil to remove.
+func (n *needLink) GiveFactory(f interface{}) ObjectInterface {
+	if f == nil {
+		return n.Give(nil)
+	}
+	_, named := n.need.(string)
+	ft := reflect.TypeOf(f)
+	switch {
+	case isBindFuncShape(ft):
+		if named || n.a.validSingletonCombination(n.need, f) {
+			return n.Give(f)
+		}
+	case n.isConstructor(ft):
+		return n.giveConstructor(reflect.ValueOf(f))
+	}
+	panic(fmt.Sprintf("Unsupported input, GiveFactory requires a BindFunc or constructor returning %s for %s, got %s", reflect.TypeOf(n.need), n.when, ft))
+}
+
+// isBindFuncShape reports whether ft takes the parameters of a BindFunc,
+// InterfaceBindFunc or ContextBindFunc.
+func isBindFuncShape(ft reflect.Type) bool {
+	if ft.Kind() != reflect.Func {
+		return false
+	}
+	switch ft.NumIn() {
+	case 1:
+		return ft.In(0) == appPtrType || ft.In(0) == appInterfaceType
+	case 2:
+		return ft.In(0) == contextType && ft.In(1) == appPtrType
+	}
+	return false
+}
+
+// giveConstructor is GiveFactory for a constructor fv.
+func (n *needLink) giveConstructor(fv reflect.Value) ObjectInterface {
+	return n.Give(BindFunc(func(r *App) interface{} {
+		out := fv.Call(r.constructorArgs(fv.Type()))
+		if len(out) == 2 && !out[1].IsNil() {
+			panic(out[1].Interface())
+		}
+		return out[0].Interface()
+	}))
+}
+
+// isConstructor reports whether ft is a function GiveFactory can call with
+// resolved parameters, returning the needed type and optionally an error.
+func (n *needLink) isConstructor(ft reflect.Type) bool {
+	if ft.Kind() != reflect.Func || ft.IsVariadic() {
+		return false
+	}
+	switch {
+	case ft.NumOut() == 1:
+	case ft.NumOut() == 2 && ft.Out(1) == errorType:
+	default:
+		return false
+	}
+	for i := 0; i < ft.NumIn(); i++ {
+		switch in := ft.In(i); in.Kind() {
+		case reflect.Ptr, reflect.Struct, reflect.Interface:
+		default:
+			if !isProviderType(in) {
+				return false
+			}
+		}
+	}
+
+	if _, ok := n.need.(string); ok {
+		// A named field or parameter, checked when the value is given
+		return true
+	}
+	t := reflect.TypeOf(n.need)
+	if it := resolveTypePtr(t); it.Kind() == reflect.Interface {
+		t = it
+	}
+	return n.a.typeChecker.IsTypeCompatible(t, ft.Out(0), false)
+}
+
+// constructorArgs resolves the parameters of a GiveFactory constructor.
+func (A *App) constructorArgs(ft reflect.Type) []reflect.Value {
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		in := ft.In(i)
+		switch {
+		case isOptionalType(in):
+			args[i] = reflect.ValueOf(A.makeOptional(in, nil))
+		case isProviderType(in):
+			args[i] = A.makeProvider(in, nil)
+		default:
+			args[i] = reflect.New(in).Elem()
+			if c := A.makeType(in); c != nil {
+				args[i].Set(reflect.ValueOf(c))
+			}
+		}
+	}
+	return args
+}
+
+// setHints stores the rule o for the dependency aKey under each requesting
+// type, for op.
+func (A *App) setHints(op string, w *whenLink, wKeys []string, aKey string, o ObjectInterface) {
+	defer A.writeLock(op)()
+	for _, wKey := range wKeys {
+		A.injectRegistry[wKey] = copyHints(A.injectRegistry[wKey], aKey, o)
+	}
+	A.addRequesters(w.interfaces())
+	if w.within {
+		A.transitive.Store(true)
+	}
+}
+
+// withinPrefix marks the hint keys of Within() rules.
+const withinPrefix = "within:"
+
+// withinHint returns the Within() rule for the dependency key given for the
+// nearest type in the resolution path, if any.
+func (A *App) withinHint(key string) (ObjectInterface, bool) {
+	if A.res == nil || len(A.res.path) == 0 || !A.hasTransitive() {
+		return nil, false
+	}
+	for i := len(A.res.path) - 1; i >= 0; i-- {
+		hintmap, _ := A.hintsFor(A.res.path[i])
+		if o, ok := hintmap[withinPrefix+key]; ok {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// hasTransitive reports whether A or a parent has ever been given a
+// Within() rule.
+func (A *App) hasTransitive() bool {
+	for c := A; c != nil; c = c.parent {
+		if c.transitive.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// keys returns the injectRegistry keys of the requesting types and the
+// dependency.
+func (n *needLink) keys() ([]string, string) {
 	a := n.need
 
-	if w == nil {
-		panic("When() requires a non-nil requesting type")
+	if len(n.when.whens) == 0 {
+		panic("When() requires a requesting type")
+	}
+	wKeys := make([]string, len(n.when.whens))
+	for i, w := range n.when.whens {
+		if w == nil {
+			panic("When() requires a non-nil requesting type")
+		}
+		wKeys[i] = typeFullName(reflect.TypeOf(w))
 	}
 	if a == nil {
 		panic("Needs() requires a non-nil dependency type")
 	}
 
-	reflectW := reflect.TypeOf(w)
-	reflectA := reflect.TypeOf(a)
-	wKey := A.typeFullName(reflectW)
-	aKey := A.typeFullName(reflectA)
+	if name, ok := a.(string); ok && isNamedHint(name) {
+		if n.when.within {
+			panic("Within() rules require a dependency type, not a field name or parameter position")
+		}
+		if len(name) == 1 {
+			panic("Needs() requires a field name or parameter position after $")
+		}
+		return wKeys, name
+	}
 
-	if b == nil {
-		object := A.injectRegistry[wKey][aKey]
-		delete(A.injectRegistry[wKey], aKey)
-		return object
+	if n.when.within {
+		return wKeys, withinPrefix + typeFullName(reflect.TypeOf(a))
 	}
+	return wKeys, typeFullName(reflect.TypeOf(a))
+}
+
+// isNamedHint reports whether a Needs key names a field, as "$Timeout", or
+// a New() parameter position, as "$0", rather than a dependency type.
+func isNamedHint(key string) bool {
+	return strings.HasPrefix(key, "$")
+}
 
-	if !A.validBindCombination(a, b) && !A.validSingletonCombination(a, b) {
-		panic(fmt.Sprintf("Can not assign %s to %s for %s", reflect.TypeOf(b), reflectA, reflectW))
+// fieldHint returns the value of a When(...).Needs("$Field") rule for the
+// inject-tagged field f. A value passed to MakeWith for the field wins.
+func (A *App) fieldHint(f reflect.StructField, hintmap map[string]ObjectInterface, injectables map[string]interface{}) (reflect.Value, bool) {
+	if _, inject := f.Tag.Lookup("inject"); !inject {
+		return reflect.Value{}, false
 	}
+	if pv, pe := injectables[f.Name]; pe && pv != nil && reflect.TypeOf(pv).AssignableTo(f.Type) {
+		return reflect.Value{}, false
+	}
+	return A.namedHint(hintmap, f.Name, f.Type)
+}
 
-	if A.injectRegistry[wKey] == nil {
-		A.injectRegistry[wKey] = make(map[string]ObjectInterface)
+// namedHint returns the value given by When(...).Needs("$name").Give(...)
+// for the field or New() parameter name of type t, if there is one.
+func (A *App) namedHint(hintmap map[string]ObjectInterface, name string, t reflect.Type) (reflect.Value, bool) {
+	o, ok := hintmap["$"+name]
+	if !ok {
+		return reflect.Value{}, false
 	}
 
-	object := A.objectBuilder.New(b)
+	c := A.processObject(o.(*Object), make(map[string]interface{}))
+	if c == nil {
+		return reflect.Zero(t), true
+	}
+	v := reflect.ValueOf(c)
+	switch {
+	case v.Type().AssignableTo(t):
+		return v, true
+	case isPrimitiveKind(t.Kind()) && v.Kind() == t.Kind():
+		return v.Convert(t), true
+	case numberFits(v, t):
+		return v.Convert(t), true
+	}
+
+	panic(fmt.Sprintf("Can not assign %s to $%s (%s)", v.Type(), name, t))
+}
 
-	A.injectRegistry[wKey][aKey] = object
+// numberFits reports whether v converts to t without losing its value:
+// integers to integer kinds and floats to float kinds that can hold them,
+// and integers to float kinds that represent them exactly.
+func numberFits(v reflect.Value, t reflect.Type) bool {
+	n := reflect.New(t).Elem()
+	switch {
+	case isSignedKind(v.Kind()) && isSignedKind(t.Kind()):
+		return !n.OverflowInt(v.Int())
+	case isSignedKind(v.Kind()) && isIntKind(t.Kind()):
+		return v.Int() >= 0 && !n.OverflowUint(uint64(v.Int()))
+	case isIntKind(v.Kind()) && isSignedKind(t.Kind()):
+		return v.Uint() <= math.MaxInt64 && !n.OverflowInt(int64(v.Uint()))
+	case isIntKind(v.Kind()) && isIntKind(t.Kind()):
+		return !n.OverflowUint(v.Uint())
+	case isFloatKind(v.Kind()) && isFloatKind(t.Kind()):
+		return !n.OverflowFloat(v.Float())
+	case isSignedKind(v.Kind()) && isFloatKind(t.Kind()):
+		f := v.Convert(t).Float()
+		return f >= math.MinInt64 && f < math.MaxInt64 && int64(f) == v.Int()
+	case isIntKind(v.Kind()) && isFloatKind(t.Kind()):
+		f := v.Convert(t).Float()
+		return f < math.MaxUint64 && uint64(f) == v.Uint()
+	}
+	return false
+}
 
-	return object
+// interfaces returns the requesting types given as a nil pointer to an
+// interface, such as (*Repository)(nil). Their rules apply to every type
+// implementing the interface.
+func (w *whenLink) interfaces() []reflect.Type {
+	var ifaces []reflect.Type
+	for _, r := range w.whens {
+		if t := reflect.TypeOf(r); t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+			ifaces = append(ifaces, t.Elem())
+		}
+	}
+	return ifaces
+}
+
+// String lists the requesting types for error messages.
+func (w *whenLink) String() string {
+	names := make([]string, len(w.whens))
+	for i, r := range w.whens {
+		names[i] = fmt.Sprint(reflect.TypeOf(r))
+	}
+	return strings.Join(names, ", ")
+}
+
+// addRequesters records interface requesting types in the order they were
+// first given a rule. The caller holds appMu.
+func (A *App) addRequesters(ifaces []reflect.Type) {
+	var added []reflect.Type
+	for _, t := range ifaces {
+		if !containsType(A.requesters, t) && !containsType(added, t) {
+			added = append(added, t)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	// Resolutions read requesters without holding the lock, so it is
+	// replaced rather than appended to in place.
+	requesters := make([]reflect.Type, 0, len(A.requesters)+len(added))
+	A.requesters = append(append(requesters, A.requesters...), added...)
+}
+
+// hintsFor returns the contextual rules for requesting type t. Rules given
+// for t itself take precedence; for dependencies they don't cover, the
+// rules of the interfaces t implements apply, the interface first given a
+// rule winning when several cover the same dependency.
+func (A *App) hintsFor(t reflect.Type) (map[string]ObjectInterface, bool) {
+	hintmap, hasmap := A.hints(A.typeFullName(t))
+
+	merged, copied := hintmap, false
+	for _, it := range A.interfaceRequesters() {
+		if !t.Implements(it) && (t.Kind() == reflect.Ptr || !reflect.PtrTo(t).Implements(it)) {
+			continue
+		}
+		ihints, ok := A.hints(A.typeFullName(reflect.PtrTo(it)))
+		if !ok {
+			continue
+		}
+		for k, o := range ihints {
+			if _, e := merged[k]; e {
+				continue
+			}
+			if !copied {
+				merged, copied = copyRegistry(hintmap), true
+			}
+			merged[k] = o
+		}
+	}
+
+	// GiveSingleton rules hold an instance per requesting type
+	key := A.typeFullName(t)
+	for k, o := range merged {
+		if x, ok := o.(*Object); ok && x.perType != nil {
+			if !copied {
+				merged, copied = copyRegistry(merged), true
+			}
+			merged[k] = x.forRequester(key)
+		}
+	}
+
+	return merged, hasmap || len(merged) > 0
+}
+
+// interfaceRequesters returns the interface requesting types of A and its
+// parents, A's first.
+func (A *App) interfaceRequesters() []reflect.Type {
+	unlock := A.readLock()
+	requesters := A.requesters
+	unlock()
+	if A.parent != nil {
+		if inherited := A.parent.interfaceRequesters(); len(inherited) > 0 {
+			return append(requesters[:len(requesters):len(requesters)], inherited...)
+		}
+	}
+	return requesters
+}
+
+// copyHints returns a copy of hints with key set to o, or removed when o is
+// nil. Resolutions read hint maps without holding the lock, so they are
+// replaced rather than written in place.
+func copyHints(hints map[string]ObjectInterface, key string, o ObjectInterface) map[string]ObjectInterface {
+	c := make(map[string]ObjectInterface, len(hints)+1)
+	for k, v := range hints {
+		c[k] = v
+	}
+	if o == nil {
+		delete(c, key)
+	} else {
+		c[key] = o
+	}
+	return c
 }
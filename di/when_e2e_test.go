@@ -1,7 +1,11 @@
 package di
 
 import (
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type WhenTestRepo interface {
@@ -555,3 +559,556 @@ func TestWhen_WhenNeedsGive_NewParamAndFieldBothOverridden(t *testing.T) {
 		t.Errorf("Expected redis for Cache (When override on inject field), got %s", handler.Cache.Get())
 	}
 }
+
+type WhenTestAudited interface {
+	Audited()
+}
+
+type WhenTestNamed interface {
+	Named() string
+}
+
+type WhenTestAuditedHandler struct {
+	Repo  WhenTestRepo  `inject:""`
+	Cache WhenTestCache `inject:""`
+}
+
+func (w *WhenTestAuditedHandler) Audited() {}
+
+func (w *WhenTestAuditedHandler) Named() string { return "audited" }
+
+type WhenTestOtherHandler struct {
+	Repo WhenTestRepo `inject:""`
+}
+
+func TestWhen_MultipleRequesters(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+	c.When(&WhenTestHandler{}, &WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).Give(&WhenTestCacheRepo{})
+
+	if c.Make(&WhenTestHandler{}).(*WhenTestHandler).Repo.Save() != "cache:" {
+		t.Error("expected rule to apply to the first requester")
+	}
+	if c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler).Repo.Save() != "cache:" {
+		t.Error("expected rule to apply to the second requester")
+	}
+	if c.Make(&WhenTestHandlerPtr{}).(*WhenTestHandlerPtr).Repo.Save() != "db:" {
+		t.Error("expected other requesters to use the default binding")
+	}
+
+	c.When(&WhenTestHandler{}, &WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).Give(nil)
+	if c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler).Repo.Save() != "db:" {
+		t.Error("expected Give(nil) to remove the rule for every requester")
+	}
+}
+
+func TestWhen_InterfaceRequester(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestRepo)(nil)).Give(&WhenTestCacheRepo{})
+
+	h := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+	if h.Repo.Save() != "cache:" {
+		t.Errorf("expected interface rule to apply, got %s", h.Repo.Save())
+	}
+	if h.Cache.Get() != "memory" {
+		t.Error("expected dependencies without a rule to use the default binding")
+	}
+	if c.Make(&WhenTestHandler{}).(*WhenTestHandler).Repo.Save() != "db:" {
+		t.Error("expected types not implementing the interface to use the default binding")
+	}
+}
+
+func TestWhen_ExactRequesterWinsOverInterface(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestRepo)(nil)).Give(&WhenTestCacheRepo{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestCache)(nil)).Give(&WhenTestRedisCache{})
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+	c.When(&WhenTestAuditedHandler{}).Needs((*WhenTestRepo)(nil)).Give(&WhenTestDBRepo{Name: "exact"})
+
+	h := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+	if h.Repo.Save() != "db:exact" {
+		t.Errorf("expected exact-type rule to win, got %s", h.Repo.Save())
+	}
+	if h.Cache.Get() != "redis" {
+		t.Error("expected interface rule to fill dependencies the exact rules don't cover")
+	}
+}
+
+func TestWhen_FirstInterfaceRequesterWins(t *testing.T) {
+	c := New()
+	c.When((*WhenTestNamed)(nil)).Needs((*WhenTestCache)(nil)).Give(&WhenTestMemoryCache{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestCache)(nil)).Give(&WhenTestRedisCache{})
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+
+	if c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler).Cache.Get() != "memory" {
+		t.Error("expected the interface first given a rule to win")
+	}
+}
+
+func TestWhen_InterfaceRequesterInScope(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestRepo)(nil)).Give(&WhenTestCacheRepo{})
+
+	s := c.Scope()
+	if s.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler).Repo.Save() != "cache:" {
+		t.Error("expected scope to inherit interface rules")
+	}
+}
+
+type WhenTestClient struct {
+	Timeout time.Duration `inject:"5s"`
+	Host    string        `inject:"localhost"`
+	Retries int           `di:"" inject:"1"`
+	Repo    WhenTestRepo  `inject:""`
+}
+
+type WhenTestEndpoint struct {
+	Host string
+	Port int
+}
+
+func (w WhenTestEndpoint) New(host string, port int) *WhenTestEndpoint {
+	return &WhenTestEndpoint{Host: host, Port: port}
+}
+
+func TestWhen_NeedsFieldName(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.When(&WhenTestClient{}).Needs("$Timeout").Give(30 * time.Second)
+	c.When(&WhenTestClient{}).Needs("$Host").Give("api.internal")
+	c.When(&WhenTestClient{}).Needs("$Retries").Give(3)
+	c.When(&WhenTestClient{}).Needs("$Repo").Give(func(a *App) interface{} {
+		return &WhenTestCacheRepo{Name: "named"}
+	})
+
+	cl := c.Make(&WhenTestClient{}).(*WhenTestClient)
+	if cl.Timeout != 30*time.Second {
+		t.Errorf("expected 30s, got %v", cl.Timeout)
+	}
+	if cl.Host != "api.internal" {
+		t.Errorf("expected the string as given rather than an alias, got %q", cl.Host)
+	}
+	if cl.Retries != 3 {
+		t.Errorf("expected named value to win over the dual tag literal, got %d", cl.Retries)
+	}
+	if cl.Repo.Save() != "cache:named" {
+		t.Errorf("expected named BindFunc to be called, got %s", cl.Repo.Save())
+	}
+}
+
+func TestWhen_NeedsFieldNameMakeWithWins(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.When(&WhenTestClient{}).Needs("$Host").Give("api.internal")
+
+	cl := c.MakeWith(&WhenTestClient{}, map[string]interface{}{"Host": "override"}).(*WhenTestClient)
+	if cl.Host != "override" {
+		t.Errorf("expected MakeWith value to win, got %q", cl.Host)
+	}
+}
+
+func TestWhen_NeedsFieldNameConvertsNumbers(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.When(&WhenTestClient{}).Needs("$Retries").Give(int64(4))
+
+	if c.Make(&WhenTestClient{}).(*WhenTestClient).Retries != 4 {
+		t.Error("expected int64 to be converted to int")
+	}
+}
+
+type WhenTestLevels struct {
+	Level uint8   `inject:"1"`
+	Count int     `inject:"1"`
+	Ratio float64 `inject:"0.5"`
+	Scale float32 `inject:"0.5"`
+}
+
+func TestWhen_NeedsFieldNameRejectsLossyNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		field string
+		value interface{}
+	}{
+		{"$Level", 300},
+		{"$Level", -1},
+		{"$Count", 2.9},
+		{"$Ratio", 1<<53 + 1},
+		{"$Scale", uint32(1<<24 + 1)},
+	} {
+		c := New()
+		c.When(&WhenTestLevels{}).Needs(tc.field).Give(tc.value)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic giving %v to %s", tc.value, tc.field)
+				}
+			}()
+			c.Make(&WhenTestLevels{})
+		}()
+	}
+
+	c := New()
+	c.When(&WhenTestLevels{}).Needs("$Level").Give(200)
+	if c.Make(&WhenTestLevels{}).(*WhenTestLevels).Level != 200 {
+		t.Error("expected an int that fits to convert to uint8")
+	}
+
+	c = New()
+	c.When(&WhenTestLevels{}).Needs("$Ratio").Give(1)
+	c.When(&WhenTestLevels{}).Needs("$Scale").Give(uint(3))
+	if l := c.Make(&WhenTestLevels{}).(*WhenTestLevels); l.Ratio != 1 || l.Scale != 3 {
+		t.Errorf("expected exactly representable integers to convert to floats, got %v and %v", l.Ratio, l.Scale)
+	}
+}
+
+func TestWhen_NeedsFieldNameWrongTypePanics(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestRepo)(nil), &WhenTestDBRepo{})
+	c.When(&WhenTestClient{}).Needs("$Retries").Give("three")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic assigning a string to an int field")
+		}
+	}()
+	c.Make(&WhenTestClient{})
+}
+
+func TestWhen_NeedsParameterPosition(t *testing.T) {
+	c := New()
+	c.When(&WhenTestEndpoint{}).Needs("$0").Give("db.internal")
+	c.When(&WhenTestEndpoint{}).Needs("$1").Give(5432)
+
+	s := c.Make(&WhenTestEndpoint{}).(*WhenTestEndpoint)
+	if s.Host != "db.internal" || s.Port != 5432 {
+		t.Errorf("expected New() parameters by position, got %+v", s)
+	}
+}
+
+func TestWhen_NeedsEmptyNamePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for Needs(\"$\")")
+		}
+	}()
+	New().When(&WhenTestClient{}).Needs("$").Give(1)
+}
+
+type WhenTestCountingRepo struct {
+	ID int64
+}
+
+func (w *WhenTestCountingRepo) Save() string {
+	return fmt.Sprintf("counting:%d", w.ID)
+}
+
+type WhenTestSingletonRepo struct {
+	Cache WhenTestCache `inject:""`
+}
+
+func (w *WhenTestSingletonRepo) Save() string {
+	return "singleton:" + w.Cache.Get()
+}
+
+func countingRepo(built *atomic.Int64) BindFunc {
+	return func(a *App) interface{} {
+		return &WhenTestCountingRepo{ID: built.Add(1)}
+	}
+}
+
+func TestWhen_GiveSingletonPerRequester(t *testing.T) {
+	var built atomic.Int64
+	c := New()
+	c.When(&WhenTestOtherHandler{}, &WhenTestAuditedHandler{}).Needs((*WhenTestRepo)(nil)).GiveSingleton(countingRepo(&built))
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+
+	o1 := c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+	o2 := c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+	a1 := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+
+	if o1.Repo != o2.Repo {
+		t.Error("expected the same instance for the same requesting type")
+	}
+	if o1.Repo == a1.Repo {
+		t.Error("expected a separate instance per requesting type")
+	}
+	if built.Load() != 2 {
+		t.Errorf("expected 2 builds, got %d", built.Load())
+	}
+}
+
+func TestWhen_GiveSingletonGlobal(t *testing.T) {
+	var built atomic.Int64
+	c := New()
+	c.When(&WhenTestOtherHandler{}, &WhenTestAuditedHandler{}).Needs((*WhenTestRepo)(nil)).GiveSingleton(countingRepo(&built), GiveConfig{Global: true})
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+
+	o := c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+	a := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+	if o.Repo != a.Repo || built.Load() != 1 {
+		t.Errorf("expected one shared instance, built %d", built.Load())
+	}
+}
+
+func TestWhen_GiveSingletonInterfaceRequester(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestCache)(nil), &WhenTestRedisCache{})
+	c.When((*WhenTestAudited)(nil)).Needs((*WhenTestRepo)(nil)).GiveSingleton((*WhenTestSingletonRepo)(nil))
+
+	h1 := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+	h2 := c.Make(&WhenTestAuditedHandler{}).(*WhenTestAuditedHandler)
+	if h1.Repo != h2.Repo {
+		t.Error("expected one instance for the implementing type")
+	}
+	if h1.Repo.Save() != "singleton:redis" {
+		t.Errorf("expected nil pointer to be auto-generated, got %s", h1.Repo.Save())
+	}
+}
+
+func TestWhen_GiveSingletonFreshInClone(t *testing.T) {
+	var built atomic.Int64
+	c := New()
+	c.When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveSingleton(countingRepo(&built))
+	orig := c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+
+	clone := c.Clone(CloneConfig{FreshSingletons: true})
+	cloned := clone.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+	if orig.Repo == cloned.Repo {
+		t.Error("expected the clone to build its own instance")
+	}
+	if c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler).Repo != orig.Repo {
+		t.Error("expected the original to keep its instance")
+	}
+}
+
+func TestWhen_GiveSingletonValidatesAsSingleton(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a struct value, which Singleton rejects")
+		}
+	}()
+	New().When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveSingleton(WhenTestDBRepo{})
+}
+
+func TestWhen_GiveFactory(t *testing.T) {
+	var built atomic.Int64
+	c := New()
+	c.When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveFactory(countingRepo(&built))
+
+	c.Make(&WhenTestOtherHandler{})
+	c.Make(&WhenTestOtherHandler{})
+	if built.Load() != 2 {
+		t.Errorf("expected the factory to run on every injection, got %d", built.Load())
+	}
+}
+
+func TestWhen_GiveFactoryConstructor(t *testing.T) {
+	var built atomic.Int64
+	c := New()
+	c.Bind((*WhenTestCache)(nil), &WhenTestMemoryCache{})
+	c.When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveFactory(func(cache WhenTestCache) *WhenTestCacheRepo {
+		built.Add(1)
+		return &WhenTestCacheRepo{Name: cache.Get()}
+	})
+
+	h := c.Make(&WhenTestOtherHandler{}).(*WhenTestOtherHandler)
+	c.Make(&WhenTestOtherHandler{})
+	if r, ok := h.Repo.(*WhenTestCacheRepo); !ok || r.Name != "memory" {
+		t.Errorf("expected the constructor's parameters to be resolved, got %#v", h.Repo)
+	}
+	if built.Load() != 2 {
+		t.Errorf("expected the constructor to run on every injection, got %d", built.Load())
+	}
+}
+
+func TestWhen_GiveFactoryConstructorError(t *testing.T) {
+	failed := errors.New("no repo")
+	c := New()
+	c.When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveFactory(func() (*WhenTestCacheRepo, error) {
+		return nil, failed
+	})
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, failed) {
+			t.Errorf("expected the constructor's error to panic, got %v", err)
+		}
+	}()
+	c.Make(&WhenTestOtherHandler{})
+}
+
+func TestWhen_GiveFactoryRejectsUnresolvableConstructor(t *testing.T) {
+	for _, f := range []interface{}{
+		func(n int) *WhenTestCacheRepo { return nil },
+		func() *WhenTestPrimaryDB { return nil },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %T", f)
+				}
+			}()
+			New().When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveFactory(f)
+		}()
+	}
+}
+
+func TestWhen_GiveFactoryRequiresFunc(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a non-function factory")
+		}
+	}()
+	New().When(&WhenTestOtherHandler{}).Needs((*WhenTestRepo)(nil)).GiveFactory(&WhenTestDBRepo{})
+}
+
+type WhenTestDB interface {
+	Name() string
+}
+
+type WhenTestPrimaryDB struct{}
+
+func (w *WhenTestPrimaryDB) Name() string { return "primary" }
+
+type WhenTestReplicaDB struct{}
+
+func (w *WhenTestReplicaDB) Name() string { return "replica" }
+
+type WhenTestArchiveDB struct{}
+
+func (w *WhenTestArchiveDB) Name() string { return "archive" }
+
+type WhenTestReportQuery struct {
+	DB WhenTestDB `inject:""`
+}
+
+type WhenTestReportGenerator struct {
+	Query *WhenTestReportQuery `inject:""`
+	DB    WhenTestDB           `inject:""`
+}
+
+type WhenTestReportService struct {
+	Generator *WhenTestReportGenerator `inject:""`
+	DB        WhenTestDB               `inject:""`
+	Later     Provider[WhenTestDB]     `inject:""`
+}
+
+type WhenTestOptionalReport struct {
+	Query *WhenTestOptionalQuery `inject:""`
+}
+
+type WhenTestOptionalQuery struct {
+	DB Optional[WhenTestDB] `inject:""`
+}
+
+func TestWhen_WithinAppliesToSubgraph(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportService{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+
+	s := c.Make(&WhenTestReportService{}).(*WhenTestReportService)
+	if s.DB.Name() != "replica" {
+		t.Errorf("expected replica for the requester itself, got %s", s.DB.Name())
+	}
+	if s.Generator.DB.Name() != "replica" || s.Generator.Query.DB.Name() != "replica" {
+		t.Error("expected replica throughout the subgraph")
+	}
+	if g := c.Make(&WhenTestReportGenerator{}).(*WhenTestReportGenerator); g.DB.Name() != "primary" {
+		t.Error("expected the default binding outside the subgraph")
+	}
+	if s.Later().Name() != "replica" {
+		t.Error("expected providers made in the subgraph to keep the rule")
+	}
+}
+
+func TestWhen_DirectRuleWinsOverWithin(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportService{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+	c.When(&WhenTestReportQuery{}).Needs((*WhenTestDB)(nil)).Give(&WhenTestArchiveDB{})
+
+	s := c.Make(&WhenTestReportService{}).(*WhenTestReportService)
+	if s.Generator.Query.DB.Name() != "archive" {
+		t.Error("expected the requester's own rule to win")
+	}
+	if s.Generator.DB.Name() != "replica" {
+		t.Error("expected the Within rule for other requesters")
+	}
+}
+
+func TestWhen_NearestWithinWins(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportService{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+	c.When(&WhenTestReportGenerator{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestArchiveDB{})
+
+	s := c.Make(&WhenTestReportService{}).(*WhenTestReportService)
+	if s.DB.Name() != "replica" {
+		t.Error("expected the outer rule above the inner requester")
+	}
+	if s.Generator.Query.DB.Name() != "archive" {
+		t.Error("expected the nearest requester's rule to win")
+	}
+}
+
+func TestWhen_WithinMakesOptionalPresent(t *testing.T) {
+	c := New()
+	c.When(&WhenTestOptionalReport{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+
+	r := c.Make(&WhenTestOptionalReport{}).(*WhenTestOptionalReport)
+	if db, ok := r.Query.DB.Get(); !ok || db.Name() != "replica" {
+		t.Error("expected Optional to be present inside the subgraph")
+	}
+	if _, ok := c.Make(&WhenTestOptionalQuery{}).(*WhenTestOptionalQuery).DB.Get(); ok {
+		t.Error("expected Optional to be absent outside the subgraph")
+	}
+}
+
+func TestWhen_WithinRemove(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportGenerator{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+	c.When(&WhenTestReportGenerator{}).Within().Needs((*WhenTestDB)(nil)).Give(nil)
+
+	if c.Make(&WhenTestReportGenerator{}).(*WhenTestReportGenerator).Query.DB.Name() != "primary" {
+		t.Error("expected Give(nil) to remove the Within rule")
+	}
+}
+
+func TestWhen_WithinKeptByClone(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportService{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+
+	s := c.Clone().Make(&WhenTestReportService{}).(*WhenTestReportService)
+	if s.Generator.Query.DB.Name() != "replica" {
+		t.Error("expected the clone to apply the Within rule")
+	}
+}
+
+func TestWhen_WithinKeptBySnapshot(t *testing.T) {
+	c := New()
+	c.Bind((*WhenTestDB)(nil), &WhenTestPrimaryDB{})
+	c.When(&WhenTestReportService{}).Within().Needs((*WhenTestDB)(nil)).Give(&WhenTestReplicaDB{})
+
+	other := New()
+	other.Restore(c.Snapshot())
+	s := other.Make(&WhenTestReportService{}).(*WhenTestReportService)
+	if s.Generator.Query.DB.Name() != "replica" {
+		t.Error("expected the restored container to apply the Within rule")
+	}
+}
+
+func TestWhen_WithinNamedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a Within rule on a field name")
+		}
+	}()
+	New().When(&WhenTestReportService{}).Within().Needs("$DB").Give(&WhenTestReplicaDB{})
+}